@@ -0,0 +1,114 @@
+package robfigcronschedule
+
+import "time"
+
+// alignedAfter returns the first point of the alignment grid after t. The
+// clock grid repeats every day at the offset plus multiples of the interval
+// on the wall clock; the anchor grid is the anchor plus the offset plus any
+// multiple of the interval. Callers must check that alignment is enabled.
+func (s *Schedule) alignedAfter(t time.Time) time.Time {
+	step := s.fixedInterval()
+
+	if s.alignMode == AlignAnchor {
+		origin := s.alignAnchor.Add(s.alignOffset)
+		// Move the origin next to t in whole seconds first: the interval is
+		// a whole number of seconds, and t.Sub saturates for anchors more
+		// than about 292 years away
+		stepSeconds := int64(step / time.Second)
+		skipped := floorDiv(t.Unix()-origin.Unix(), stepSeconds) * stepSeconds
+		origin = time.Unix(origin.Unix()+skipped, int64(origin.Nanosecond()))
+
+		steps := t.Sub(origin) / step
+		if next := origin.Add(steps * step); next.After(t) {
+			// t is before the origin, where the division rounds up
+			return next.In(t.Location())
+		}
+		return origin.Add((steps + 1) * step).In(t.Location())
+	}
+
+	year, month, day := t.Date()
+	pos := time.Duration(clockSeconds(t))*time.Second + time.Duration(t.Nanosecond())
+	next := s.alignOffset
+	if pos >= s.alignOffset {
+		next += ((pos-s.alignOffset)/step + 1) * step
+	}
+
+	// Clocks may be set back or forward, so the next wall-clock time is not
+	// always after t
+	for i := 0; i < maxAlignSteps; i++ {
+		if next >= 24*time.Hour {
+			day, next = day+1, s.alignOffset
+		}
+
+		times, shifted := wallClock(year, month, day, 0, 0, 0, int(next), t.Location())
+		for _, candidate := range times {
+			if candidate.After(t) {
+				return candidate
+			}
+		}
+		if len(times) == 0 && shifted.After(t) {
+			return shifted
+		}
+		next += step
+	}
+	return time.Time{}
+}
+
+// nextAligned is step 7 of Next with alignment: the next grid point after t,
+// moved to the first grid point of the next allowed day if its day is not
+// allowed. Unlike unaligned steps, runs do not restart at midnight.
+func (s *Schedule) nextAligned(t time.Time) time.Time {
+	s.trace.step(7, RuleInterval, "align %s to the %s grid",
+		formatTraceInterval(s.interval, s.intervalTimeUnit), s.alignMode)
+
+	next := s.alignedAfter(t)
+	if !next.IsZero() && !s.isDayAllowed(next) {
+		s.trace.rejectDay(s, next, RuleInterval)
+		next = s.alignStart(s.findNextAllowedDay(startOfNextDay(next)))
+		s.trace.propose(next, RuleWeekday, "first aligned run of the next allowed day")
+		return next
+	}
+
+	s.trace.propose(next, RuleInterval, "")
+	return next
+}
+
+// alignStart returns the first run at or after start: start itself, or the
+// first point of the alignment grid from there when alignment is enabled.
+func (s *Schedule) alignStart(start time.Time) time.Time {
+	if s.alignMode == AlignNone {
+		return start
+	}
+	return s.alignedAfter(start.Add(-time.Nanosecond))
+}
+
+// floorDiv returns a divided by b, rounded down.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// validateAlignment checks that alignment, if enabled, uses a fixed-length
+// unit and an offset below the interval. The clock grid also needs an
+// interval of at most a day.
+func validateAlignment(s *Schedule) error {
+	switch s.alignMode {
+	case AlignNone:
+		return nil
+	case AlignClock, AlignAnchor:
+	default:
+		return ErrInvalidAlignment
+	}
+
+	step := s.fixedInterval()
+	if step <= 0 || s.alignOffset < 0 || s.alignOffset >= step {
+		return ErrInvalidAlignment
+	}
+	if s.alignMode == AlignClock && step > 24*time.Hour {
+		return ErrInvalidAlignment
+	}
+	return nil
+}
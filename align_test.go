@@ -0,0 +1,211 @@
+package robfigcronschedule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Alignment(t *testing.T) {
+	anchor := parseTime(t, "2025-01-06 09:00:00")
+	startDate := parseTime(t, "2025-01-06 09:03:00")
+	weekdays := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+	}
+
+	tests := []struct {
+		name     string
+		interval int
+		unit     IntervalTimeUnit
+		opts     []ScheduleOption
+		from     string
+		expected []string
+	}{
+		{
+			name:     "quarter hours",
+			interval: 15,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToClock(0)},
+			from:     "2025-01-06 10:07:00",
+			expected: []string{"2025-01-06 10:15:00", "2025-01-06 10:30:00", "2025-01-06 10:45:00"},
+		},
+		{
+			name:     "every hour at :07",
+			interval: 1,
+			unit:     Hour,
+			opts:     []ScheduleOption{AlignToClock(7 * time.Minute)},
+			from:     "2025-01-06 10:07:00",
+			expected: []string{"2025-01-06 11:07:00", "2025-01-06 12:07:00", "2025-01-06 13:07:00"},
+		},
+		{
+			name:     "clock grid restarts at midnight",
+			interval: 25,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToClock(0)},
+			from:     "2025-01-06 23:40:00",
+			expected: []string{"2025-01-06 23:45:00", "2025-01-07 00:00:00", "2025-01-07 00:25:00"},
+		},
+		{
+			name:     "anchor grid continues past midnight",
+			interval: 25,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToAnchor(parseTime(t, "2025-01-06 00:00:00"), 0)},
+			from:     "2025-01-06 23:40:00",
+			expected: []string{"2025-01-06 23:45:00", "2025-01-07 00:10:00", "2025-01-07 00:35:00"},
+		},
+		{
+			name:     "anchor grid before the anchor",
+			interval: 90,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToAnchor(anchor, 5*time.Minute)},
+			from:     "2025-01-06 06:10:00",
+			expected: []string{"2025-01-06 07:35:00", "2025-01-06 09:05:00", "2025-01-06 10:35:00"},
+		},
+		{
+			name:     "anchor at the zero time",
+			interval: 15,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToAnchor(time.Time{}, 0)},
+			from:     "2025-01-06 10:00:00",
+			expected: []string{"2025-01-06 10:15:00", "2025-01-06 10:30:00", "2025-01-06 10:45:00"},
+		},
+		{
+			name:     "anchor centuries before",
+			interval: 15,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToAnchor(parseTime(t, "1700-01-01 00:07:00"), 0)},
+			from:     "2025-01-06 10:00:00",
+			expected: []string{"2025-01-06 10:07:00", "2025-01-06 10:22:00", "2025-01-06 10:37:00"},
+		},
+		{
+			name:     "time window opens between grid points",
+			interval: 15,
+			unit:     Minute,
+			opts: []ScheduleOption{
+				AlignToClock(0),
+				SetTimeWindows(TimeWindow{Start: clock(9, 10), End: clock(9, 50)}),
+			},
+			from: "2025-01-06 08:00:00",
+			expected: []string{
+				"2025-01-06 09:15:00", "2025-01-06 09:30:00", "2025-01-06 09:45:00",
+				"2025-01-07 09:15:00",
+			},
+		},
+		{
+			name:     "skips to the first grid point of the next allowed day",
+			interval: 1,
+			unit:     Hour,
+			opts: []ScheduleOption{
+				AlignToClock(7 * time.Minute),
+				SetAllowedWeekdays(weekdays...),
+			},
+			from:     "2025-01-10 22:30:00",
+			expected: []string{"2025-01-10 23:07:00", "2025-01-13 00:07:00", "2025-01-13 01:07:00"},
+		},
+		{
+			name:     "start date between grid points",
+			interval: 15,
+			unit:     Minute,
+			opts:     []ScheduleOption{AlignToClock(0), SetStartDate(&startDate)},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-01-06 09:15:00", "2025-01-06 09:30:00", "2025-01-06 09:45:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(tt.interval, tt.unit, tt.opts...)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(parseTime(t, tt.from), len(tt.expected))
+			expected := make([]time.Time, len(tt.expected))
+			for i, run := range tt.expected {
+				expected[i] = parseTime(t, run)
+			}
+			assert.Equal(t, expected, runs)
+
+			// Prev walks the same runs backwards
+			for i := 1; i < len(runs); i++ {
+				assert.Equal(t, runs[i-1], schedule.Prev(runs[i]))
+			}
+		})
+	}
+}
+
+func TestSchedule_AlignmentDSTGap(t *testing.T) {
+	newYork, _ := loadDSTZones(t)
+
+	// 02:00 and 02:30 do not exist on the spring-forward day
+	schedule, err := New(30, Minute, AlignToClock(0))
+	require.NoError(t, err)
+
+	runs := schedule.NextN(parseZoned(t, newYork, "2025-03-09 01:45:00 -0500"), 2)
+	assert.Equal(t, []string{
+		"2025-03-09 03:00:00 -0400", "2025-03-09 03:30:00 -0400",
+	}, formatZoned(runs...))
+}
+
+func TestSchedule_AlignmentValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		unit     IntervalTimeUnit
+		opt      ScheduleOption
+	}{
+		{name: "calendar unit", interval: 1, unit: Day, opt: AlignToClock(0)},
+		{name: "offset of an interval", interval: 1, unit: Hour, opt: AlignToClock(time.Hour)},
+		{name: "negative offset", interval: 1, unit: Hour, opt: AlignToClock(-time.Second)},
+		{name: "clock grid over a day", interval: 25, unit: Hour, opt: AlignToClock(0)},
+		{name: "unknown mode", interval: 1, unit: Hour, opt: func(s *Schedule) { s.alignMode = 3 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.interval, tt.unit, tt.opt)
+			assert.ErrorIs(t, err, ErrInvalidAlignment)
+		})
+	}
+
+	// The anchor grid is not limited to a day
+	_, err := New(25, Hour, AlignToAnchor(time.Now(), 0))
+	assert.NoError(t, err)
+
+	// Removing the alignment restores plain stepping
+	s, err := New(15, Minute, AlignToClock(0), DisableAlignment())
+	require.NoError(t, err)
+	from := parseTime(t, "2025-01-06 10:07:00")
+	assert.Equal(t, parseTime(t, "2025-01-06 10:22:00"), s.Next(from))
+}
+
+func TestSchedule_AlignmentConfig(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	original, err := New(90, Minute,
+		AlignToAnchor(time.Date(2025, 1, 6, 9, 0, 0, 0, jakarta), 5*time.Minute))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(original.Config())
+	require.NoError(t, err)
+	assert.Contains(t, string(data),
+		`"align":"anchor","align_anchor":"2025-01-06T09:00:00+07:00","align_offset":"5m0s"`)
+
+	var config Config
+	require.NoError(t, json.Unmarshal(data, &config))
+	decoded, err := NewFromConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, AlignAnchor, decoded.alignMode)
+	assert.True(t, original.alignAnchor.Equal(decoded.alignAnchor))
+	assert.Equal(t, 5*time.Minute, decoded.alignOffset)
+
+	now := time.Date(2025, 1, 8, 10, 10, 0, 0, jakarta)
+	assert.Equal(t, original.next(now), decoded.next(now))
+
+	// An anchor is required
+	_, err = NewFromConfig(Config{Interval: 1, Unit: Hour, Align: AlignAnchor})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
@@ -0,0 +1,55 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+)
+
+// benchmarkUnits lists every interval unit, for benchmarks that compare them.
+var benchmarkUnits = []IntervalTimeUnit{Second, Minute, Hour, Day, Week, Month, Year, BusinessDay}
+
+// BenchmarkRoundUp compares rounding up from a window that opened at midnight
+// to a time late in the day by stepping through every run, as non-precision
+// mode used to, with the computed result.
+func BenchmarkRoundUp(b *testing.B) {
+	open := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2025, 1, 6, 23, 59, 59, 0, time.UTC)
+
+	for _, unit := range benchmarkUnits {
+		schedule, err := New(1, unit, DisablePrecision())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(unit.String()+"/stepping", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				schedule.roundUpByStepping(open, at)
+			}
+		})
+		b.Run(unit.String()+"/computed", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				schedule.roundUp(open, at)
+			}
+		})
+	}
+}
+
+// BenchmarkNextImprecise measures Next in non-precision mode inside a time
+// window that opened at midnight, late in the day.
+func BenchmarkNextImprecise(b *testing.B) {
+	midnight := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2025, 1, 6, 23, 59, 58, 0, time.UTC)
+
+	for _, unit := range benchmarkUnits {
+		schedule, err := New(1, unit, SetStartTime(&midnight), DisablePrecision())
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(unit.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				schedule.next(at)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package robfigcronschedule
+
+import "time"
+
+// addBusinessDays adds n allowed days to t, keeping its wall-clock time. Days
+// rejected by the weekday rules or blocked by the calendar are not counted.
+// Returns the zero time if more than maxScanDays rejected or maxBlockedDays
+// blocked days follow each other.
+func (s *Schedule) addBusinessDays(t time.Time, n int) time.Time {
+	day := t
+	for count, rejected, blocked := 0, 0, 0; count < n; {
+		day = addDate(day, 0, 0, 1)
+		switch {
+		case s.isDayAllowed(day):
+			count, rejected, blocked = count+1, 0, 0
+		case s.isBlocked(day):
+			if blocked++; blocked > maxBlockedDays {
+				return time.Time{}
+			}
+		default:
+			if rejected++; rejected > maxScanDays {
+				return time.Time{}
+			}
+		}
+	}
+	return day
+}
@@ -0,0 +1,98 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_BusinessDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		opts     []ScheduleOption
+		from     string
+		expected []string
+	}{
+		{
+			name:     "skips weekends",
+			interval: 3,
+			from:     "2025-01-09 00:00:00",
+			expected: []string{"2025-01-14 00:00:00", "2025-01-17 00:00:00", "2025-01-22 00:00:00"},
+		},
+		{
+			name:     "skips holidays",
+			interval: 3,
+			opts:     []ScheduleOption{SetCalendar(NewDateSet(parseTime(t, "2025-01-20 00:00:00")))},
+			from:     "2025-01-09 00:00:00",
+			expected: []string{"2025-01-14 00:00:00", "2025-01-17 00:00:00", "2025-01-23 00:00:00"},
+		},
+		{
+			name:     "custom working week",
+			interval: 1,
+			opts: []ScheduleOption{SetAllowedWeekdays(
+				time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+			)},
+			from:     "2025-01-09 00:00:00",
+			expected: []string{"2025-01-12 00:00:00", "2025-01-13 00:00:00"},
+		},
+		{
+			name:     "weekend start moves to Monday",
+			interval: 1,
+			from:     "2025-01-11 10:00:00",
+			expected: []string{"2025-01-13 00:00:00", "2025-01-14 00:00:00"},
+		},
+		{
+			name:     "long interval",
+			interval: 30,
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-02-12 00:00:00"},
+		},
+		{
+			name:     "time window",
+			interval: 2,
+			opts: []ScheduleOption{SetTimeWindows(TimeWindow{
+				Start: time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2000, 1, 1, 17, 0, 0, 0, time.UTC),
+			})},
+			from:     "2025-01-10 10:00:00",
+			expected: []string{"2025-01-14 09:00:00", "2025-01-16 09:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(tt.interval, BusinessDay, tt.opts...)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(parseTime(t, tt.from), len(tt.expected))
+			expected := make([]time.Time, len(tt.expected))
+			for i, run := range tt.expected {
+				expected[i] = parseTime(t, run)
+			}
+			assert.Equal(t, expected, runs)
+		})
+	}
+}
+
+func TestSchedule_BusinessDayFormats(t *testing.T) {
+	schedule, err := Parse("every 3 bdays")
+	require.NoError(t, err)
+	assert.Equal(t, BusinessDay, schedule.intervalTimeUnit)
+	assert.Equal(t, "every 3bd", schedule.String())
+	assert.Equal(t, "Every 3 business days", schedule.Describe())
+	assert.Equal(t, "Setiap 3 hari kerja", schedule.DescribeIn(Indonesian))
+
+	assert.Equal(t, BusinessDay, schedule.Config().Unit)
+	text, err := BusinessDay.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "business_day", string(text))
+	var unit IntervalTimeUnit
+	require.NoError(t, unit.UnmarshalText([]byte("Business_Day")))
+	assert.Equal(t, BusinessDay, unit)
+
+	_, err = schedule.RRule()
+	assert.ErrorIs(t, err, ErrRRuleNotRepresentable)
+}
@@ -0,0 +1,140 @@
+package robfigcronschedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout used for dates in calendar files.
+const dateLayout = "2006-01-02"
+
+// Calendar reports dates on which the schedule must not run, such as public
+// holidays or change freezes. Blocked dates are skipped the same way as
+// disallowed weekdays.
+type Calendar interface {
+	// IsBlocked reports whether the calendar date of date, in date's location,
+	// is blocked.
+	IsBlocked(date time.Time) bool
+}
+
+// RangeCalendar is a Calendar that can also block time ranges within a day,
+// such as a two-hour maintenance freeze. A run that falls inside a blocked
+// range is recomputed from the end of that range.
+type RangeCalendar interface {
+	Calendar
+
+	// BlockedUntil reports whether t falls inside a blocked time range and,
+	// if so, when that range ends.
+	BlockedUntil(t time.Time) (until time.Time, blocked bool)
+}
+
+// civilDate is a calendar date without a time or location.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dateOf returns the calendar date of t in t's location.
+func dateOf(t time.Time) civilDate {
+	year, month, day := t.Date()
+	return civilDate{year: year, month: month, day: day}
+}
+
+// DateSet is an in-memory Calendar that blocks a fixed set of dates.
+// Dates are compared by their calendar date in their own location, so
+// 2024-12-25 blocks Christmas Day in whatever location the schedule runs.
+type DateSet struct {
+	dates map[civilDate]struct{}
+}
+
+// NewDateSet creates a DateSet blocking the calendar date of each given time.
+//
+// Example:
+//
+//	holidays := NewDateSet(
+//	    time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+//	    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+//	)
+//	New(1, Day, SetCalendar(holidays))
+func NewDateSet(dates ...time.Time) *DateSet {
+	set := &DateSet{dates: make(map[civilDate]struct{}, len(dates))}
+	for _, date := range dates {
+		set.dates[dateOf(date)] = struct{}{}
+	}
+	return set
+}
+
+// IsBlocked reports whether date's calendar date is in the set.
+func (d *DateSet) IsBlocked(date time.Time) bool {
+	_, ok := d.dates[dateOf(date)]
+	return ok
+}
+
+// Len returns the number of blocked dates.
+func (d *DateSet) Len() int {
+	return len(d.dates)
+}
+
+// ReadDateSet reads a DateSet from r. The input holds one YYYY-MM-DD date per
+// line. Blank lines and lines starting with '#' are ignored, as is anything
+// after the date separated by whitespace, so lines can carry a description.
+//
+// Example input:
+//
+//	# Public holidays 2025
+//	2025-01-01 New Year's Day
+//	2025-12-25 Christmas Day
+func ReadDateSet(r io.Reader) (*DateSet, error) {
+	var dates []time.Time
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		field := strings.Fields(text)[0]
+		date, err := time.Parse(dateLayout, field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %q", ErrInvalidCalendarDate, line, field)
+		}
+		dates = append(dates, date)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewDateSet(dates...), nil
+}
+
+// LoadDateSetFile reads a DateSet from the file at path.
+// See ReadDateSet for the file format.
+//
+// Example:
+//
+//	holidays, err := LoadDateSetFile("/etc/myapp/holidays.txt")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	New(1, Day, SetCalendar(holidays))
+func LoadDateSetFile(path string) (*DateSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadDateSet(f)
+}
+
+// isBlocked reports whether the calendar blocks t's date.
+// Always false when no calendar is set.
+func (s *Schedule) isBlocked(t time.Time) bool {
+	return s.calendar != nil && s.calendar.IsBlocked(t)
+}
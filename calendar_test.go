@@ -0,0 +1,140 @@
+package robfigcronschedule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateSet_IsBlocked(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	set := NewDateSet(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, 1, set.Len())
+
+	assert.True(t, set.IsBlocked(time.Date(2024, 12, 25, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, set.IsBlocked(time.Date(2024, 12, 25, 1, 0, 0, 0, jakarta)))
+	assert.False(t, set.IsBlocked(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestReadDateSet(t *testing.T) {
+	input := `
+# Public holidays 2025
+2025-01-01 New Year's Day
+
+2025-12-25	Christmas Day
+`
+	set, err := ReadDateSet(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, 2, set.Len())
+	assert.True(t, set.IsBlocked(parseTime(t, "2025-01-01 12:00:00")))
+	assert.True(t, set.IsBlocked(parseTime(t, "2025-12-25 12:00:00")))
+
+	_, err = ReadDateSet(strings.NewReader("2025-01-01\n25/12/2025\n"))
+	assert.ErrorIs(t, err, ErrInvalidCalendarDate)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLoadDateSetFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.txt")
+	require.NoError(t, os.WriteFile(path, []byte("2024-03-11\n"), 0o600))
+
+	set, err := LoadDateSetFile(path)
+	require.NoError(t, err)
+	assert.True(t, set.IsBlocked(parseTime(t, "2024-03-11 00:00:00")))
+
+	_, err = LoadDateSetFile(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSchedule_CalendarBlocksDates(t *testing.T) {
+	startTime := time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)
+	holidays := NewDateSet(
+		parseTime(t, "2024-03-11 00:00:00"), // Monday
+		parseTime(t, "2024-03-12 00:00:00"), // Tuesday
+	)
+
+	tests := []struct {
+		name     string
+		interval int
+		unit     IntervalTimeUnit
+		opts     []ScheduleOption
+		current  string
+		expected string
+	}{
+		{
+			name:     "time window skips holidays",
+			interval: 1,
+			unit:     Day,
+			opts: []ScheduleOption{
+				SetStartTime(&startTime),
+				SetAllowedWeekdays(
+					time.Monday,
+					time.Tuesday,
+					time.Wednesday,
+					time.Thursday,
+					time.Friday,
+				),
+			},
+			current:  "2024-03-08 10:00:00", // Friday
+			expected: "2024-03-13 09:00:00", // Wednesday
+		},
+		{
+			name:     "interval skips holidays",
+			interval: 30,
+			unit:     Minute,
+			current:  "2024-03-10 23:45:00", // Sunday
+			expected: "2024-03-13 00:00:00", // Wednesday
+		},
+		{
+			name:     "today is a holiday",
+			interval: 30,
+			unit:     Minute,
+			current:  "2024-03-11 10:00:00",
+			expected: "2024-03-13 00:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]ScheduleOption{SetCalendar(holidays)}, tt.opts...)
+			schedule, err := New(tt.interval, tt.unit, opts...)
+			require.NoError(t, err)
+
+			current := parseTime(t, tt.current)
+			expected := parseTime(t, tt.expected)
+
+			next := schedule.Next(current)
+			assert.Equal(t, expected, next)
+		})
+	}
+}
+
+func TestSchedule_CalendarLongFreeze(t *testing.T) {
+	// A change freeze longer than the weekday scan limit
+	var frozen []time.Time
+	day := parseTime(t, "2024-12-01 00:00:00")
+	for ; day.Month() == time.December; day = day.AddDate(0, 0, 1) {
+		frozen = append(frozen, day)
+	}
+
+	startTime := time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)
+	schedule, err := New(1, Day,
+		SetStartTime(&startTime),
+		SetAllowedWeekdays(time.Monday),
+		SetCalendar(NewDateSet(frozen...)),
+	)
+	require.NoError(t, err)
+
+	next := schedule.Next(parseTime(t, "2024-11-30 10:00:00"))
+	assert.Equal(t, parseTime(t, "2025-01-06 09:00:00"), next) // first Monday after freeze
+
+	next = schedule.findNextAllowedDay(parseTime(t, "2024-11-30 10:00:00"))
+	assert.Equal(t, parseTime(t, "2025-01-06 09:00:00"), next)
+}
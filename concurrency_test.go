@@ -0,0 +1,137 @@
+package robfigcronschedule
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests are meant to be run with -race.
+
+func TestSchedule_ConcurrentNextAndSet(t *testing.T) {
+	startTime := time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)
+	endTime := time.Date(2000, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	s, err := New(1, Minute,
+		SetStartTime(&startTime),
+		SetEndTime(&endTime),
+		SetMaxRuns(1000),
+		SetAfterNextFunc(func(next *time.Time) {}),
+	)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				s.Next(base.Add(time.Duration(i*500+j) * time.Minute))
+			}
+		}(i)
+	}
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				err := s.Set(
+					SetInterval(1+j%10),
+					SetAllowedWeekdays(time.Weekday(j%7)),
+					SetTimeWindows(),
+				)
+				assert.NoError(t, err)
+
+				if j%3 == 0 {
+					assert.NoError(t, s.Set(Disable()))
+					assert.NoError(t, s.Set(Enable()))
+				}
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 200; j++ {
+			s.RemainingRuns()
+			_ = s.String()
+			_, _ = s.RRule()
+			_, err := json.Marshal(s)
+			assert.NoError(t, err)
+		}
+	}()
+
+	wg.Wait()
+
+	runs := s.RemainingRuns()
+	assert.GreaterOrEqual(t, runs, 0)
+	assert.Less(t, runs, 1000)
+}
+
+func TestSchedule_ConcurrentHooksCallSet(t *testing.T) {
+	var mu sync.Mutex
+	interval := 5
+
+	s, err := New(5, Second,
+		SetBeforeNextFunc(func(s *Schedule) {
+			mu.Lock()
+			i := interval
+			mu.Unlock()
+			// Hooks run without the schedule's lock held
+			assert.NoError(t, s.Set(SetInterval(i)))
+		}),
+	)
+	require.NoError(t, err)
+
+	base := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				next := s.Next(base.Add(time.Duration(i*200+j) * time.Second))
+				assert.False(t, next.IsZero())
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 200; j++ {
+			mu.Lock()
+			interval = 1 + j%7
+			mu.Unlock()
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestSchedule_SetNextRunDuringHook(t *testing.T) {
+	base := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	override := base.Add(time.Hour)
+
+	var s *Schedule
+	var once sync.Once
+	s, err := New(5, Second,
+		SetAfterNextFunc(func(*time.Time) {
+			// Stands in for a Set from another goroutine while the hook runs
+			once.Do(func() { assert.NoError(t, s.Set(SetNextRun(&override))) })
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, base.Add(5*time.Second), s.Next(base))
+	assert.Equal(t, override, s.Next(base))
+}
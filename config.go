@@ -0,0 +1,718 @@
+package robfigcronschedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConfigVersion is the Config format version written by Schedule.Config.
+const ConfigVersion = 1
+
+// clockLayout is the layout used for times of day in a Config. Fractions of
+// a second are only written when present.
+const clockLayout = "15:04:05.999999999"
+
+// Config is the serializable configuration of a Schedule, suitable for storing
+// in a database or a JSON/YAML file.
+//
+// Dates use RFC 3339 and times of day use "15:04:05" (or "15:04"), both with
+// fractions of a second when present. All of them are interpreted in
+// Timezone, an IANA location name that defaults to UTC.
+// Weekdays are lower-case English names such as "monday".
+//
+// Runtime state (next run and hooks), the calendar and the jitter source are
+// not part of the configuration.
+//
+// Example (YAML):
+//
+//	version: 1
+//	interval: 30
+//	unit: minute
+//	timezone: Asia/Jakarta
+//	start_time: "09:00:00"
+//	end_time: "17:00:00"
+//	weekdays: [monday, tuesday, wednesday, thursday, friday]
+type Config struct {
+	// Version is the format version. 0 is treated as ConfigVersion.
+	Version int `json:"version" yaml:"version"`
+
+	Interval int              `json:"interval" yaml:"interval"`
+	Unit     IntervalTimeUnit `json:"unit" yaml:"unit"`
+
+	// Enabled and Precision default to true when omitted.
+	Enabled   *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Precision *bool `json:"precision,omitempty" yaml:"precision,omitempty"`
+
+	// DisabledMode is "recheck" (default) or "sleep". DisabledRecheck is a
+	// duration such as "30s", empty for the default. PausedUntil is the end of
+	// a pause set by PauseUntil.
+	DisabledMode    DisabledMode `json:"disabled_mode,omitempty" yaml:"disabled_mode,omitempty"`
+	DisabledRecheck string       `json:"disabled_recheck,omitempty" yaml:"disabled_recheck,omitempty"`
+	PausedUntil     string       `json:"paused_until,omitempty" yaml:"paused_until,omitempty"`
+
+	// PinTimezone evaluates the schedule in Timezone, see SetLocation.
+	Timezone    string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	PinTimezone bool   `json:"pin_timezone,omitempty" yaml:"pin_timezone,omitempty"`
+
+	// NthDayOfMonth is the nth allowed day of the month, see
+	// SetNthDayOfMonth. MonthEnd is "rollover" (default), "clamp" or "skip",
+	// see SetMonthEndPolicy.
+	NthDayOfMonth int            `json:"nth_day_of_month,omitempty" yaml:"nth_day_of_month,omitempty"`
+	MonthEnd      MonthEndPolicy `json:"month_end,omitempty" yaml:"month_end,omitempty"`
+
+	// DSTGap is "shift" (default) or "skip", DSTOverlap is "once" (default)
+	// or "twice". See SetDSTPolicy.
+	DSTGap     DSTGapPolicy     `json:"dst_gap,omitempty" yaml:"dst_gap,omitempty"`
+	DSTOverlap DSTOverlapPolicy `json:"dst_overlap,omitempty" yaml:"dst_overlap,omitempty"`
+
+	// Align is "none" (default), "clock" or "anchor". AlignAnchor is the
+	// anchor date of "anchor" and AlignOffset a duration such as "7m". See
+	// AlignToClock and AlignToAnchor.
+	Align       AlignMode `json:"align,omitempty" yaml:"align,omitempty"`
+	AlignAnchor string    `json:"align_anchor,omitempty" yaml:"align_anchor,omitempty"`
+	AlignOffset string    `json:"align_offset,omitempty" yaml:"align_offset,omitempty"`
+
+	// Jitter and Splay are durations such as "5m", SplayKey the key of the
+	// splay. See SetJitter and SetSplay.
+	Jitter   string `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	Splay    string `json:"splay,omitempty" yaml:"splay,omitempty"`
+	SplayKey string `json:"splay_key,omitempty" yaml:"splay_key,omitempty"`
+
+	StartDate string `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+	StartTime string `json:"start_time,omitempty" yaml:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty" yaml:"end_time,omitempty"`
+
+	TimeWindows       []WindowConfig            `json:"time_windows,omitempty" yaml:"time_windows,omitempty"`
+	WeeklyTimeWindows map[string][]WindowConfig `json:"weekly_time_windows,omitempty" yaml:"weekly_time_windows,omitempty"`
+	Weekdays          []string                  `json:"weekdays,omitempty" yaml:"weekdays,omitempty"`
+
+	// MaxRuns is the run limit, 0 means unlimited. RemainingRuns defaults to
+	// MaxRuns when omitted.
+	MaxRuns       int  `json:"max_runs,omitempty" yaml:"max_runs,omitempty"`
+	RemainingRuns *int `json:"remaining_runs,omitempty" yaml:"remaining_runs,omitempty"`
+}
+
+// WindowConfig is the serializable form of a TimeWindow. An empty End means
+// the end of the day.
+type WindowConfig struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+// nameTable holds the names of an enum type's values, as written by String
+// and used in configurations.
+type nameTable[T ~int] struct {
+	typeName string // Go type name, for String of unknown values
+	kind     string // name in errors, such as "unit"
+	names    map[T]string
+}
+
+// has reports whether v is a known value.
+func (t nameTable[T]) has(v T) bool {
+	_, ok := t.names[v]
+	return ok
+}
+
+// name returns the name of v, or the type name and number of unknown values.
+func (t nameTable[T]) name(v T) string {
+	if name, ok := t.names[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", t.typeName, int(v))
+}
+
+// marshal returns the name of v. Unknown values return ErrInvalidConfig.
+func (t nameTable[T]) marshal(v T) ([]byte, error) {
+	if !t.has(v) {
+		return nil, fmt.Errorf("%w: unknown %s %d", ErrInvalidConfig, t.kind, int(v))
+	}
+	return []byte(t.names[v]), nil
+}
+
+// unmarshal sets *v to the value named text, ignoring case. Unknown names
+// return ErrInvalidConfig.
+func (t nameTable[T]) unmarshal(text []byte, v *T) error {
+	for value, name := range t.names {
+		if strings.EqualFold(name, string(text)) {
+			*v = value
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown %s %q", ErrInvalidConfig, t.kind, text)
+}
+
+var intervalTimeUnitNames = nameTable[IntervalTimeUnit]{
+	typeName: "IntervalTimeUnit",
+	kind:     "unit",
+	names: map[IntervalTimeUnit]string{
+		Second:      "second",
+		Minute:      "minute",
+		Hour:        "hour",
+		Day:         "day",
+		Week:        "week",
+		Month:       "month",
+		Year:        "year",
+		BusinessDay: "business_day",
+	},
+}
+
+// String returns the lower-case name of the unit, such as "minute".
+func (u IntervalTimeUnit) String() string { return intervalTimeUnitNames.name(u) }
+
+// MarshalText encodes the unit as its name.
+func (u IntervalTimeUnit) MarshalText() ([]byte, error) { return intervalTimeUnitNames.marshal(u) }
+
+// UnmarshalText decodes a unit name, ignoring case.
+func (u *IntervalTimeUnit) UnmarshalText(text []byte) error {
+	return intervalTimeUnitNames.unmarshal(text, u)
+}
+
+var disabledModeNames = nameTable[DisabledMode]{
+	typeName: "DisabledMode",
+	kind:     "disabled mode",
+	names: map[DisabledMode]string{
+		DisabledRecheck: "recheck",
+		DisabledSleep:   "sleep",
+	},
+}
+
+// String returns the lower-case name of the mode, such as "sleep".
+func (m DisabledMode) String() string { return disabledModeNames.name(m) }
+
+// MarshalText encodes the mode as its name.
+func (m DisabledMode) MarshalText() ([]byte, error) { return disabledModeNames.marshal(m) }
+
+// UnmarshalText decodes a mode name, ignoring case.
+func (m *DisabledMode) UnmarshalText(text []byte) error {
+	return disabledModeNames.unmarshal(text, m)
+}
+
+var monthEndPolicyNames = nameTable[MonthEndPolicy]{
+	typeName: "MonthEndPolicy",
+	kind:     "month end policy",
+	names: map[MonthEndPolicy]string{
+		MonthEndRollover: "rollover",
+		MonthEndClamp:    "clamp",
+		MonthEndSkip:     "skip",
+	},
+}
+
+// String returns the lower-case name of the policy, such as "clamp".
+func (p MonthEndPolicy) String() string { return monthEndPolicyNames.name(p) }
+
+// MarshalText encodes the policy as its name.
+func (p MonthEndPolicy) MarshalText() ([]byte, error) { return monthEndPolicyNames.marshal(p) }
+
+// UnmarshalText decodes a policy name, ignoring case.
+func (p *MonthEndPolicy) UnmarshalText(text []byte) error {
+	return monthEndPolicyNames.unmarshal(text, p)
+}
+
+var alignModeNames = nameTable[AlignMode]{
+	typeName: "AlignMode",
+	kind:     "align mode",
+	names: map[AlignMode]string{
+		AlignNone:   "none",
+		AlignClock:  "clock",
+		AlignAnchor: "anchor",
+	},
+}
+
+// String returns the lower-case name of the mode, such as "clock".
+func (m AlignMode) String() string { return alignModeNames.name(m) }
+
+// MarshalText encodes the mode as its name.
+func (m AlignMode) MarshalText() ([]byte, error) { return alignModeNames.marshal(m) }
+
+// UnmarshalText decodes a mode name, ignoring case.
+func (m *AlignMode) UnmarshalText(text []byte) error {
+	return alignModeNames.unmarshal(text, m)
+}
+
+var dstGapPolicyNames = nameTable[DSTGapPolicy]{
+	typeName: "DSTGapPolicy",
+	kind:     "DST gap policy",
+	names: map[DSTGapPolicy]string{
+		DSTShiftForward: "shift",
+		DSTSkip:         "skip",
+	},
+}
+
+// String returns the lower-case name of the policy, such as "skip".
+func (p DSTGapPolicy) String() string { return dstGapPolicyNames.name(p) }
+
+// MarshalText encodes the policy as its name.
+func (p DSTGapPolicy) MarshalText() ([]byte, error) { return dstGapPolicyNames.marshal(p) }
+
+// UnmarshalText decodes a policy name, ignoring case.
+func (p *DSTGapPolicy) UnmarshalText(text []byte) error {
+	return dstGapPolicyNames.unmarshal(text, p)
+}
+
+var dstOverlapPolicyNames = nameTable[DSTOverlapPolicy]{
+	typeName: "DSTOverlapPolicy",
+	kind:     "DST overlap policy",
+	names: map[DSTOverlapPolicy]string{
+		DSTRunOnce:  "once",
+		DSTRunTwice: "twice",
+	},
+}
+
+// String returns the lower-case name of the policy, such as "twice".
+func (p DSTOverlapPolicy) String() string { return dstOverlapPolicyNames.name(p) }
+
+// MarshalText encodes the policy as its name.
+func (p DSTOverlapPolicy) MarshalText() ([]byte, error) { return dstOverlapPolicyNames.marshal(p) }
+
+// UnmarshalText decodes a policy name, ignoring case.
+func (p *DSTOverlapPolicy) UnmarshalText(text []byte) error {
+	return dstOverlapPolicyNames.unmarshal(text, p)
+}
+
+// Config returns the schedule's configuration. Times are written in the
+// location of the first configured date or time window; times in other
+// locations are converted to it.
+//
+// Example:
+//
+//	data, err := json.Marshal(schedule.Config())
+func (s *Schedule) Config() Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enabled, precision := s.enabled, s.precision
+	c := Config{
+		Version:   ConfigVersion,
+		Interval:  s.interval,
+		Unit:      s.intervalTimeUnit,
+		Enabled:   &enabled,
+		Precision: &precision,
+		MaxRuns:   s.maxRuns,
+
+		DisabledMode:  s.disabledMode,
+		NthDayOfMonth: s.nthDay,
+		MonthEnd:      s.monthEnd,
+		DSTGap:        s.dstGap,
+		DSTOverlap:    s.dstOverlap,
+		Align:         s.alignMode,
+	}
+
+	loc := s.configLocation()
+	if loc != nil {
+		c.Timezone = loc.String()
+	}
+	c.PinTimezone = s.location != nil
+
+	if s.disabledRecheck != 0 {
+		c.DisabledRecheck = s.disabledRecheck.String()
+	}
+	if s.pausedUntil != nil {
+		pauseLoc := loc
+		if pauseLoc == nil {
+			pauseLoc = time.UTC
+		}
+		c.PausedUntil = s.pausedUntil.In(pauseLoc).Format(time.RFC3339Nano)
+	}
+
+	if s.alignMode == AlignAnchor {
+		anchorLoc := loc
+		if anchorLoc == nil {
+			anchorLoc = time.UTC
+		}
+		c.AlignAnchor = s.alignAnchor.In(anchorLoc).Format(time.RFC3339Nano)
+	}
+	if s.alignOffset != 0 {
+		c.AlignOffset = s.alignOffset.String()
+	}
+	if s.jitter != 0 {
+		c.Jitter = s.jitter.String()
+	}
+	if s.splayMax != 0 {
+		c.Splay = s.splayMax.String()
+		c.SplayKey = s.splayKey
+	}
+
+	if s.startDate != nil {
+		c.StartDate = s.startDate.In(loc).Format(time.RFC3339Nano)
+	}
+	if s.endDate != nil {
+		c.EndDate = s.endDate.In(loc).Format(time.RFC3339Nano)
+	}
+	if s.startTime != nil {
+		c.StartTime = s.startTime.In(loc).Format(clockLayout)
+	}
+	if s.endTime != nil {
+		c.EndTime = s.endTime.In(loc).Format(clockLayout)
+	}
+
+	for _, w := range s.timeWindows {
+		c.TimeWindows = append(c.TimeWindows, windowConfig(w, loc))
+	}
+	if s.weeklyWindows != nil {
+		c.WeeklyTimeWindows = make(map[string][]WindowConfig, len(s.weeklyWindows))
+		for day, windows := range s.weeklyWindows {
+			key := strings.ToLower(day.String())
+			c.WeeklyTimeWindows[key] = []WindowConfig{}
+			for _, w := range windows {
+				c.WeeklyTimeWindows[key] = append(c.WeeklyTimeWindows[key], windowConfig(w, loc))
+			}
+		}
+	}
+
+	if s.allowedWeekdays != nil {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if (*s.allowedWeekdays)[day] {
+				c.Weekdays = append(c.Weekdays, strings.ToLower(day.String()))
+			}
+		}
+	}
+
+	if s.maxRuns > 0 {
+		remaining := s.remainingRuns
+		c.RemainingRuns = &remaining
+	}
+
+	return c
+}
+
+// configLocation returns the location set with SetLocation, or else the
+// location of the first configured date or time window, or nil if there is
+// none.
+func (s *Schedule) configLocation() *time.Location {
+	if s.location != nil {
+		return s.location
+	}
+
+	candidates := []*time.Time{s.startDate, s.endDate, s.startTime, s.endTime}
+	if s.alignMode == AlignAnchor {
+		candidates = append(candidates, &s.alignAnchor)
+	}
+	for i := range s.timeWindows {
+		candidates = append(candidates, &s.timeWindows[i].Start)
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		for i := range s.weeklyWindows[day] {
+			candidates = append(candidates, &s.weeklyWindows[day][i].Start)
+		}
+	}
+
+	for _, t := range candidates {
+		if t != nil {
+			return t.Location()
+		}
+	}
+	return nil
+}
+
+// windowConfig converts w to its serializable form in loc.
+func windowConfig(w TimeWindow, loc *time.Location) WindowConfig {
+	c := WindowConfig{Start: w.Start.In(loc).Format(clockLayout)}
+	if !w.End.IsZero() {
+		c.End = w.End.In(loc).Format(clockLayout)
+	}
+	return c
+}
+
+// Options converts the configuration to options that replace every
+// configurable setting of a schedule. Use them with Set to apply a stored
+// configuration to an existing schedule, keeping its hooks and calendar.
+//
+// Returns ErrInvalidConfig if a value cannot be parsed and
+// ErrUnsupportedConfigVersion for configurations newer than ConfigVersion.
+// Settings are validated by Set or New as usual.
+//
+// Example:
+//
+//	opts, err := config.Options()
+//	if err != nil {
+//	    return err
+//	}
+//	err = schedule.Set(opts...)
+func (c Config) Options() ([]ScheduleOption, error) {
+	if c.Version < 0 || c.Version > ConfigVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedConfigVersion, c.Version)
+	}
+
+	loc := time.UTC
+	if c.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: timezone: %v", ErrInvalidConfig, err)
+		}
+	}
+
+	opts := []ScheduleOption{
+		SetInterval(c.Interval),
+		SetIntervalTimeUnit(c.Unit),
+		SetLocation(nil),
+		Enable(),
+		EnablePrecision(),
+	}
+	if c.PinTimezone {
+		opts = append(opts, SetLocation(loc))
+	}
+	if c.Enabled != nil && !*c.Enabled {
+		opts = append(opts, Disable())
+	}
+	if c.Precision != nil && !*c.Precision {
+		opts = append(opts, DisablePrecision())
+	}
+
+	var recheck time.Duration
+	if c.DisabledRecheck != "" {
+		var err error
+		if recheck, err = time.ParseDuration(c.DisabledRecheck); err != nil {
+			return nil, fmt.Errorf("%w: disabled_recheck: %v", ErrInvalidConfig, err)
+		}
+	}
+	opts = append(opts, SetDisabledMode(c.DisabledMode), SetDisabledRecheckInterval(recheck))
+	opts = append(opts, SetNthDayOfMonth(c.NthDayOfMonth), SetMonthEndPolicy(c.MonthEnd))
+	opts = append(opts, SetDSTPolicy(c.DSTGap, c.DSTOverlap))
+
+	pausedUntil, err := parseConfigDate("paused_until", c.PausedUntil, loc)
+	if err != nil {
+		return nil, err
+	}
+	if pausedUntil != nil {
+		opts = append(opts, PauseUntil(*pausedUntil))
+	}
+
+	alignOpt, err := c.alignOption(loc)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, alignOpt)
+
+	var jitter, splay time.Duration
+	if c.Jitter != "" {
+		if jitter, err = time.ParseDuration(c.Jitter); err != nil {
+			return nil, fmt.Errorf("%w: jitter: %v", ErrInvalidConfig, err)
+		}
+	}
+	if c.Splay != "" {
+		if splay, err = time.ParseDuration(c.Splay); err != nil {
+			return nil, fmt.Errorf("%w: splay: %v", ErrInvalidConfig, err)
+		}
+	}
+	opts = append(opts, SetJitter(jitter), SetSplay(c.SplayKey, splay))
+
+	startDate, err := parseConfigDate("start_date", c.StartDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseConfigDate("end_date", c.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := parseConfigClock("start_time", c.StartTime, loc)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := parseConfigClock("end_time", c.EndTime, loc)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts,
+		SetStartDate(startDate),
+		SetEndDate(endDate),
+		SetStartTime(startTime),
+		SetEndTime(endTime),
+	)
+
+	windows, err := parseConfigWindows("time_windows", c.TimeWindows, loc)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, SetTimeWindows(windows...))
+
+	var table map[time.Weekday][]TimeWindow
+	if c.WeeklyTimeWindows != nil {
+		table = make(map[time.Weekday][]TimeWindow, len(c.WeeklyTimeWindows))
+		for name, dayWindows := range c.WeeklyTimeWindows {
+			day, err := parseConfigWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			if table[day], err = parseConfigWindows(name, dayWindows, loc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	opts = append(opts, SetWeeklyTimeWindows(table))
+
+	var weekdays []time.Weekday
+	for _, name := range c.Weekdays {
+		day, err := parseConfigWeekday(name)
+		if err != nil {
+			return nil, err
+		}
+		weekdays = append(weekdays, day)
+	}
+	opts = append(opts, SetAllowedWeekdays(weekdays...), SetMaxRuns(c.MaxRuns))
+	if c.RemainingRuns != nil {
+		opts = append(opts, SetRemainingRuns(*c.RemainingRuns))
+	}
+
+	return opts, nil
+}
+
+// NewFromConfig creates a Schedule from a stored configuration. Additional
+// options, such as hooks or a calendar, are applied after the configuration.
+// The result is validated like New.
+//
+// Example:
+//
+//	var config Config
+//	if err := yaml.Unmarshal(data, &config); err != nil {
+//	    return err
+//	}
+//	schedule, err := NewFromConfig(config, SetCalendar(holidays))
+func NewFromConfig(c Config, opts ...ScheduleOption) (*Schedule, error) {
+	configOpts, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(c.Interval, c.Unit, append(configOpts, opts...)...)
+}
+
+// MarshalJSON encodes the schedule's Config.
+func (s *Schedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Config())
+}
+
+// UnmarshalJSON decodes a Config and applies it with Set, so invalid
+// configurations leave the schedule unchanged. Hooks and the calendar are
+// kept.
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalYAML encodes the schedule's Config. It implements the
+// gopkg.in/yaml.v3 Marshaler interface.
+func (s *Schedule) MarshalYAML() (interface{}, error) {
+	return s.Config(), nil
+}
+
+// UnmarshalYAML decodes a Config and applies it like UnmarshalJSON. It
+// implements the gopkg.in/yaml Unmarshaler interface.
+func (s *Schedule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var c Config
+	if err := unmarshal(&c); err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// setConfig applies c to the schedule. The options replace every configurable
+// setting, so this also works on a zero Schedule created by a decoder.
+func (s *Schedule) setConfig(c Config) error {
+	opts, err := c.Options()
+	if err != nil {
+		return err
+	}
+	return s.Set(opts...)
+}
+
+// alignOption returns the option for the Align, AlignAnchor and AlignOffset
+// fields, with the anchor in loc.
+func (c Config) alignOption(loc *time.Location) (ScheduleOption, error) {
+	var offset time.Duration
+	if c.AlignOffset != "" {
+		var err error
+		if offset, err = time.ParseDuration(c.AlignOffset); err != nil {
+			return nil, fmt.Errorf("%w: align_offset: %v", ErrInvalidConfig, err)
+		}
+	}
+
+	switch c.Align {
+	case AlignClock:
+		return AlignToClock(offset), nil
+	case AlignAnchor:
+		anchor, err := parseConfigDate("align_anchor", c.AlignAnchor, loc)
+		if err != nil {
+			return nil, err
+		}
+		if anchor == nil {
+			return nil, fmt.Errorf("%w: align_anchor: missing anchor", ErrInvalidConfig)
+		}
+		return AlignToAnchor(*anchor, offset), nil
+	}
+	return DisableAlignment(), nil
+}
+
+// parseConfigDate parses an RFC 3339 date into loc. Empty values return nil.
+func parseConfigDate(field, value string, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, field, err)
+	}
+	t = t.In(loc)
+	return &t, nil
+}
+
+// parseConfigClock parses a "15:04:05" or "15:04" time of day in loc. Empty
+// values return nil.
+func parseConfigClock(field, value string, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(clockLayout, value)
+	if err != nil {
+		if t, err = time.Parse("15:04", value); err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid time of day %q", ErrInvalidConfig, field, value)
+		}
+	}
+	t = time.Date(2000, 1, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	return &t, nil
+}
+
+// parseConfigWindows parses serialized time windows in loc.
+func parseConfigWindows(
+	field string,
+	configs []WindowConfig,
+	loc *time.Location,
+) ([]TimeWindow, error) {
+	windows := make([]TimeWindow, 0, len(configs))
+	for _, c := range configs {
+		start, err := parseConfigClock(field, c.Start, loc)
+		if err != nil {
+			return nil, err
+		}
+		if start == nil {
+			return nil, fmt.Errorf("%w: %s: window without start", ErrInvalidConfig, field)
+		}
+
+		w := TimeWindow{Start: *start}
+		end, err := parseConfigClock(field, c.End, loc)
+		if err != nil {
+			return nil, err
+		}
+		if end != nil {
+			w.End = *end
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// parseConfigWeekday parses a case-insensitive English weekday name.
+func parseConfigWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown weekday %q", ErrInvalidConfig, name)
+}
@@ -0,0 +1,299 @@
+package robfigcronschedule
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchedule_ConfigRoundTrip(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	startDate := time.Date(2025, 1, 6, 0, 0, 0, 0, jakarta)
+	endDate := time.Date(2025, 6, 30, 18, 0, 0, 0, jakarta)
+	startTime := time.Date(0, 1, 1, 9, 0, 0, 0, jakarta)
+	endTime := time.Date(0, 1, 1, 17, 30, 0, 0, jakarta)
+
+	original, err := New(30, Minute,
+		SetStartDate(&startDate),
+		SetEndDate(&endDate),
+		SetStartTime(&startTime),
+		SetEndTime(&endTime),
+		SetAllowedWeekdays(time.Monday, time.Wednesday, time.Friday),
+		SetMaxRuns(10),
+		SetRemainingRuns(4),
+		DisablePrecision(),
+	)
+	require.NoError(t, err)
+
+	assertSameSchedule := func(t *testing.T, decoded *Schedule) {
+		assert.Equal(t, original.interval, decoded.interval)
+		assert.Equal(t, original.intervalTimeUnit, decoded.intervalTimeUnit)
+		assert.Equal(t, original.enabled, decoded.enabled)
+		assert.Equal(t, original.precision, decoded.precision)
+		assert.Equal(t, original.maxRuns, decoded.maxRuns)
+		assert.Equal(t, original.remainingRuns, decoded.remainingRuns)
+		assert.Equal(t, *original.allowedWeekdays, *decoded.allowedWeekdays)
+
+		require.NotNil(t, decoded.startDate)
+		assert.True(t, original.startDate.Equal(*decoded.startDate))
+		assert.Equal(t, "Asia/Jakarta", decoded.startDate.Location().String())
+		require.NotNil(t, decoded.endDate)
+		assert.True(t, original.endDate.Equal(*decoded.endDate))
+
+		require.NotNil(t, decoded.startTime)
+		assert.Equal(t, "09:00:00", decoded.startTime.Format(clockLayout))
+		assert.Equal(t, "Asia/Jakarta", decoded.startTime.Location().String())
+		require.NotNil(t, decoded.endTime)
+		assert.Equal(t, "17:30:00", decoded.endTime.Format(clockLayout))
+
+		now := time.Date(2025, 1, 8, 10, 10, 0, 0, jakarta)
+		assert.Equal(t, original.next(now), decoded.next(now))
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(original)
+		require.NoError(t, err)
+
+		var decoded Schedule
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assertSameSchedule(t, &decoded)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := yaml.Marshal(original)
+		require.NoError(t, err)
+
+		var decoded Schedule
+		require.NoError(t, yaml.Unmarshal(data, &decoded))
+		assertSameSchedule(t, &decoded)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		decoded, err := NewFromConfig(original.Config())
+		require.NoError(t, err)
+		assertSameSchedule(t, decoded)
+	})
+}
+
+func TestSchedule_ConfigRRuleRoundTrip(t *testing.T) {
+	// BYHOUR windows end at the last instant of their last hour
+	rule := "RRULE:FREQ=MINUTELY;INTERVAL=30;BYHOUR=9,10,11"
+	original, err := ParseRRule(rule)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(original.Config())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"end_time":"11:59:59.999999999"`)
+
+	var config Config
+	require.NoError(t, json.Unmarshal(data, &config))
+	decoded, err := NewFromConfig(config)
+	require.NoError(t, err)
+
+	got, err := decoded.RRule()
+	require.NoError(t, err)
+	assert.Equal(t, rule, got)
+}
+
+func TestSchedule_ConfigWindows(t *testing.T) {
+	data := `
+version: 1
+interval: 1
+unit: hour
+timezone: Europe/Berlin
+time_windows:
+  - start: "08:00"
+    end: "12:00"
+  - start: "22:00"
+    end: "02:00"
+`
+	var config Config
+	require.NoError(t, yaml.Unmarshal([]byte(data), &config))
+
+	s, err := NewFromConfig(config)
+	require.NoError(t, err)
+	require.Len(t, s.timeWindows, 2)
+	assert.Equal(t, "Europe/Berlin", s.timeWindows[1].End.Location().String())
+
+	encoded, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"interval": 1,
+		"unit": "hour",
+		"enabled": true,
+		"precision": true,
+		"timezone": "Europe/Berlin",
+		"time_windows": [
+			{"start": "08:00:00", "end": "12:00:00"},
+			{"start": "22:00:00", "end": "02:00:00"}
+		]
+	}`, string(encoded))
+
+	weekly := `{
+		"interval": 15,
+		"unit": "Minute",
+		"timezone": "Asia/Jakarta",
+		"weekly_time_windows": {
+			"monday": [{"start": "09:00", "end": "17:00"}],
+			"Saturday": [{"start": "10:00"}]
+		}
+	}`
+	var decoded Schedule
+	require.NoError(t, json.Unmarshal([]byte(weekly), &decoded))
+	assert.Len(t, decoded.weeklyWindows[time.Monday], 1)
+	assert.True(t, decoded.weeklyWindows[time.Saturday][0].End.IsZero())
+	assert.True(t, decoded.enabled)
+	assert.True(t, decoded.precision)
+}
+
+func TestSchedule_ConfigPinTimezone(t *testing.T) {
+	s, err := Parse("every 1h on mon pinned tz=Asia/Jakarta")
+	require.NoError(t, err)
+
+	c := s.Config()
+	assert.Equal(t, "Asia/Jakarta", c.Timezone)
+	assert.True(t, c.PinTimezone)
+
+	decoded, err := NewFromConfig(c)
+	require.NoError(t, err)
+	require.NotNil(t, decoded.location)
+	assert.Equal(t, "Asia/Jakarta", decoded.location.String())
+
+	// Applying an unpinned configuration unpins the schedule
+	c.PinTimezone = false
+	opts, err := c.Options()
+	require.NoError(t, err)
+	require.NoError(t, decoded.Set(opts...))
+	assert.Nil(t, decoded.location)
+}
+
+func TestSchedule_ConfigPause(t *testing.T) {
+	var s Schedule
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"interval": 1,
+		"unit": "hour",
+		"disabled_mode": "Sleep",
+		"disabled_recheck": "30s",
+		"paused_until": "2025-01-06T06:00:00+07:00"
+	}`), &s))
+	assert.Equal(t, DisabledSleep, s.disabledMode)
+	assert.False(t, s.enabled)
+	assert.Equal(t, 30*time.Second, s.disabledRecheck)
+	require.NotNil(t, s.pausedUntil)
+	assert.True(t, time.Date(2025, 1, 5, 23, 0, 0, 0, time.UTC).Equal(*s.pausedUntil))
+
+	encoded, err := json.Marshal(&s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"interval": 1,
+		"unit": "hour",
+		"enabled": false,
+		"precision": true,
+		"disabled_mode": "sleep",
+		"disabled_recheck": "30s",
+		"paused_until": "2025-01-05T23:00:00Z"
+	}`, string(encoded))
+
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "hour", "dst_gap": "Skip"}`), &s)
+	require.NoError(t, err)
+	assert.Equal(t, DSTSkip, s.dstGap)
+	encoded, err = json.Marshal(&s)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"dst_gap":"skip"`)
+	assert.NotContains(t, string(encoded), "dst_overlap")
+
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "month", "month_end": "clamp"}`), &s)
+	require.NoError(t, err)
+	assert.Equal(t, MonthEndClamp, s.monthEnd)
+
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "hour", "month_end": "round"}`), &s)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "hour", "dst_overlap": "thrice"}`), &s)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "hour", "disabled_recheck": "soon"}`), &s)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	err = json.Unmarshal([]byte(`{"interval": 1, "unit": "hour", "disabled_mode": "nap"}`), &s)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestSchedule_ConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected error
+	}{
+		{
+			name:     "invalid interval",
+			data:     `{"interval": 0, "unit": "minute"}`,
+			expected: ErrInvalidInterval,
+		},
+		{
+			name:     "equal window bounds",
+			data:     `{"interval": 5, "unit": "minute", "start_time": "09:00", "end_time": "09:00"}`,
+			expected: ErrInvalidTimeWindow,
+		},
+		{
+			name:     "weekdays with multi-week interval",
+			data:     `{"interval": 2, "unit": "week", "weekdays": ["monday"]}`,
+			expected: ErrMultiIntervalWithWeekdayWindow,
+		},
+		{
+			name:     "unknown timezone",
+			data:     `{"interval": 1, "unit": "day", "timezone": "Mars/Olympus"}`,
+			expected: ErrInvalidConfig,
+		},
+		{
+			name:     "unknown weekday",
+			data:     `{"interval": 1, "unit": "day", "weekdays": ["caturday"]}`,
+			expected: ErrInvalidConfig,
+		},
+		{
+			name:     "malformed date",
+			data:     `{"interval": 1, "unit": "day", "start_date": "06/01/2025"}`,
+			expected: ErrInvalidConfig,
+		},
+		{
+			name:     "newer version",
+			data:     `{"version": 2, "interval": 1, "unit": "day"}`,
+			expected: ErrUnsupportedConfigVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(10, Second)
+			require.NoError(t, err)
+
+			err = json.Unmarshal([]byte(tt.data), s)
+			assert.ErrorIs(t, err, tt.expected)
+
+			// Rolled back like Set
+			assert.Equal(t, 10, s.interval)
+			assert.Equal(t, Second, s.intervalTimeUnit)
+		})
+	}
+
+	var s Schedule
+	err := json.Unmarshal([]byte(`{"interval": 1, "unit": "fortnight"}`), &s)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestSchedule_ConfigKeepsHooks(t *testing.T) {
+	calls := 0
+	s, err := New(5, Second, SetBeforeNextFunc(func(*Schedule) { calls++ }))
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"interval": 3, "unit": "second"}`), s))
+	assert.Equal(t, 3, s.interval)
+
+	s.Next(time.Now())
+	assert.Equal(t, 1, calls)
+}
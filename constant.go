@@ -1,6 +1,9 @@
 package robfigcronschedule
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 // IntervalTimeUnit represents the time unit for scheduling intervals.
 type IntervalTimeUnit int
@@ -13,14 +16,168 @@ const (
 	Week
 	Month
 	Year
+	// BusinessDay counts only allowed days: the allowed weekdays, Monday to
+	// Friday by default, that the calendar does not block.
+	BusinessDay
+)
+
+// DisabledMode controls what Next returns while the schedule is disabled.
+type DisabledMode int
+
+const (
+	// DisabledRecheck makes Next return t + the recheck interval, so
+	// robfig/cron wakes the job periodically (default).
+	DisabledRecheck DisabledMode = iota
+	// DisabledSleep makes Next return the zero time, so robfig/cron stops
+	// running the job until the wake hook re-adds it.
+	DisabledSleep
+)
+
+// AlignMode selects the grid that runs of Second, Minute and Hour intervals
+// are aligned to.
+type AlignMode int
+
+const (
+	// AlignNone steps by the interval from the time Next is given (default).
+	AlignNone AlignMode = iota
+	// AlignClock runs at multiples of the interval since midnight on the
+	// wall clock, such as :00, :15, :30 and :45 for 15 minutes.
+	AlignClock
+	// AlignAnchor runs at multiples of the interval since an anchor time.
+	AlignAnchor
 )
 
+// maxAlignSteps bounds how many clock grid points are tried when a DST
+// transition moves the wall clock past the next one.
+const maxAlignSteps = 1000
+
+// MonthEndPolicy controls Month and Year steps from a day of the month that
+// the target month lacks, such as from January 31 to February.
+type MonthEndPolicy int
+
+const (
+	// MonthEndRollover overflows into the next month like time.AddDate, so
+	// January 31 + 1 month is March 3 (default).
+	MonthEndRollover MonthEndPolicy = iota
+	// MonthEndClamp runs on the last day of the month instead, so January
+	// 31 + 1 month is February 28.
+	MonthEndClamp
+	// MonthEndSkip skips months that lack the day, so January 31 + 1 month
+	// is March 31.
+	MonthEndSkip
+)
+
+// DSTGapPolicy controls runs at wall-clock times that do not exist because
+// clocks spring forward, such as 02:30 in New York on the second Sunday of
+// March.
+type DSTGapPolicy int
+
+const (
+	// DSTShiftForward moves the run forward by the length of the gap, so
+	// 02:30 becomes 03:30 (default). A time window that would open in the
+	// gap opens at the shifted time and keeps its length if it would
+	// otherwise end before opening.
+	DSTShiftForward DSTGapPolicy = iota
+	// DSTSkip drops the run. A time window that would open in the gap does
+	// not open that day.
+	DSTSkip
+)
+
+// DSTOverlapPolicy controls runs at wall-clock times that occur twice because
+// clocks fall back, such as 01:30 in New York on the first Sunday of November.
+type DSTOverlapPolicy int
+
+const (
+	// DSTRunOnce runs at the first occurrence only (default).
+	DSTRunOnce DSTOverlapPolicy = iota
+	// DSTRunTwice runs at both occurrences: a time window opens again at
+	// the second one.
+	DSTRunTwice
+)
+
+// maxScanDays bounds how many days rejected by weekday or time window rules are
+// scanned when looking for the next allowed day or time window.
+const maxScanDays = 15
+
+// maxBlockedDays bounds how many days blocked by a Calendar are skipped when
+// looking for the next allowed day. They are counted separately from
+// maxScanDays so long holiday periods or change freezes do not end the scan.
+const maxBlockedDays = 2 * 366
+
+// defaultDisabledRecheck is how far ahead Next schedules the next check while
+// the schedule is disabled, unless SetDisabledRecheckInterval changes it.
+const defaultDisabledRecheck = 5 * time.Minute
+
+// maxPreviewOccurrences bounds how many runs Between returns, so a range
+// query on a Second interval cannot exhaust memory.
+const maxPreviewOccurrences = 10000
+
 var (
 	ErrInvalidInterval = errors.New(
 		"invalid interval. interval cannot be less than 1",
 	)
+	ErrInvalidRunLimit = errors.New(
+		"invalid run limit. runs cannot be negative or exceed the maximum runs",
+	)
+	ErrInvalidRecheckInterval = errors.New(
+		"invalid recheck interval. interval cannot be negative",
+	)
+	ErrInvalidDisabledMode = errors.New(
+		"invalid disabled mode. use DisabledRecheck or DisabledSleep",
+	)
+	ErrInvalidMonthEndPolicy = errors.New(
+		"invalid month end policy. use MonthEndRollover, MonthEndClamp or MonthEndSkip",
+	)
+	ErrInvalidAlignment = errors.New(
+		"invalid alignment. use a Second, Minute or Hour unit and an offset below the interval",
+	)
+	ErrInvalidNthDay = errors.New(
+		"invalid nth day of month. use 1 to 31 or -1 to -31 with the Month unit",
+	)
+	ErrInvalidJitter = errors.New(
+		"invalid jitter. jitter and splay cannot be negative",
+	)
+	ErrInvalidDSTPolicy = errors.New(
+		"invalid DST policy. unknown gap or overlap policy",
+	)
 	ErrInvalidTimeWindow = errors.New(
-		"invalid time window. start time must be before end time",
+		"invalid time window. start time must differ from end time",
+	)
+	ErrInvalidDateRange = errors.New(
+		"invalid date range. end date must not be before start date",
+	)
+	ErrConflictingTimeWindows = errors.New(
+		"conflicting time windows. time windows cannot be combined with start/end time",
+	)
+	ErrOverlappingTimeWindows = errors.New(
+		"overlapping time windows. daily time windows must not overlap",
+	)
+	ErrInvalidWeekday = errors.New(
+		"invalid weekday. weekday must be between Sunday and Saturday",
+	)
+	ErrInvalidCalendarDate = errors.New(
+		"invalid calendar date. dates must use the YYYY-MM-DD format",
+	)
+	ErrInvalidICS = errors.New(
+		"invalid iCalendar data",
+	)
+	ErrInvalidRRule = errors.New(
+		"invalid RRULE",
+	)
+	ErrRRuleNotRepresentable = errors.New(
+		"schedule cannot be represented as an RRULE",
+	)
+	ErrInvalidConfig = errors.New(
+		"invalid schedule config",
+	)
+	ErrUnsupportedConfigVersion = errors.New(
+		"unsupported schedule config version",
+	)
+	ErrInvalidScheduleString = errors.New(
+		"invalid schedule string",
+	)
+	ErrTooManyOccurrences = errors.New(
+		"too many occurrences. narrow the time range",
 	)
 	ErrMultiIntervalWithWeekdayWindow = errors.New(
 		"multi weeks/months/years intervals with weekday restrictions may produce unexpected results",
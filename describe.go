@@ -0,0 +1,292 @@
+package robfigcronschedule
+
+import (
+	"strings"
+	"time"
+)
+
+// Locale supplies the words used by DescribeIn, so descriptions can be
+// translated. English and Indonesian are provided. Times of day, dates and
+// ranges are combined by DescribeIn; a Locale only phrases them.
+//
+// Phrases for less common settings are asked through optional interfaces,
+// such as PausedLocale, so new ones do not break existing locales. DescribeIn
+// uses English for those a locale does not implement.
+type Locale interface {
+	// Every describes the interval, such as "Every 30 minutes".
+	Every(interval int, unit IntervalTimeUnit) string
+	// Weekday returns the name of day, such as "Monday".
+	Weekday(day time.Weekday) string
+	// Date formats the calendar date of t, such as "6 Jan 2025".
+	Date(t time.Time) string
+	// List joins items, such as "Monday, Wednesday and Friday".
+	List(items []string) string
+	// Starting and Until describe the start and end date.
+	Starting(date string) string
+	Until(date string) string
+	// RunLimit describes the run limit and the runs left.
+	RunLimit(maxRuns, remainingRuns int) string
+	// Calendar notes that dates blocked by a calendar are skipped.
+	Calendar() string
+	// Imprecise notes that runs are rounded up to the interval grid.
+	Imprecise() string
+	// Disabled marks a description of a disabled schedule.
+	Disabled(description string) string
+}
+
+// PausedLocale is implemented by locales that describe paused schedules.
+type PausedLocale interface {
+	// Paused marks a description of a schedule paused until a date.
+	Paused(description, until string) string
+}
+
+// NthDayLocale is implemented by locales that describe SetNthDayOfMonth.
+type NthDayLocale interface {
+	// NthDay describes the nth allowed day of the month, counted from the end
+	// when n is negative. weekday names the only allowed weekday, or is empty.
+	NthDay(n int, weekday string) string
+}
+
+// AlignedLocale is implemented by locales that describe AlignToClock and
+// AlignToAnchor.
+type AlignedLocale interface {
+	// Aligned describes the alignment of runs to anchor, a date, or to the
+	// clock when anchor is empty. offset is a duration such as "7m", or empty.
+	Aligned(anchor, offset string) string
+}
+
+// MonthEndLocale is implemented by locales that describe SetMonthEndPolicy.
+type MonthEndLocale interface {
+	// MonthEnd describes a month end policy other than MonthEndRollover.
+	MonthEnd(policy MonthEndPolicy) string
+}
+
+// DSTLocale is implemented by locales that describe SetDSTPolicy.
+type DSTLocale interface {
+	// DSTGap describes a gap policy other than DSTShiftForward.
+	DSTGap(policy DSTGapPolicy) string
+	// DSTOverlap describes an overlap policy other than DSTRunOnce.
+	DSTOverlap(policy DSTOverlapPolicy) string
+}
+
+// JitterLocale is implemented by locales that describe SetJitter and
+// SetSplay.
+type JitterLocale interface {
+	// Jitter describes the splay and the random delay of runs, durations such
+	// as "5m". Either may be empty.
+	Jitter(splay, jitter string) string
+}
+
+// Describe returns an English description of the schedule, such as
+// "Every 30 minutes, 09:00–17:00, Monday–Friday, starting 6 Jan 2025
+// (Asia/Jakarta)". Runtime state such as hooks and the cached next run is not
+// described, nor is how a disabled schedule rechecks or sleeps.
+func (s *Schedule) Describe() string {
+	return s.DescribeIn(English)
+}
+
+// DescribeIn returns a description of the schedule in the given locale.
+//
+// Example:
+//
+//	schedule.DescribeIn(Indonesian)
+//	// Setiap 30 menit, 09:00–17:00, Senin–Jumat, mulai 6 Jan 2025 (Asia/Jakarta)
+func (s *Schedule) DescribeIn(l Locale) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc := s.configLocation()
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	parts := []string{l.Every(s.interval, s.intervalTimeUnit)}
+
+	switch {
+	case s.startTime != nil || s.endTime != nil:
+		w := TimeWindow{Start: time.Date(2000, 1, 1, 0, 0, 0, 0, loc)}
+		if s.startTime != nil {
+			w.Start = *s.startTime
+		}
+		if s.endTime != nil {
+			w.End = *s.endTime
+		}
+		parts = append(parts, describeWindows(l, []TimeWindow{w}, loc))
+	case len(s.timeWindows) > 0:
+		parts = append(parts, describeWindows(l, s.timeWindows, loc))
+	case s.weeklyWindows != nil:
+		parts = append(parts, describeWeeklyWindows(l, s.weeklyWindows, loc))
+	}
+
+	weekday := ""
+	if s.allowedWeekdays != nil {
+		days := describeWeekdays(l, func(day time.Weekday) bool {
+			return (*s.allowedWeekdays)[day]
+		})
+		// The nth day names a single weekday itself, as in "2nd Tuesday"
+		if s.nthDay != 0 && len(*s.allowedWeekdays) == 1 {
+			weekday = days
+		} else {
+			parts = append(parts, days)
+		}
+	}
+	if s.nthDay != 0 {
+		nth, ok := l.(NthDayLocale)
+		if !ok {
+			nth = english{}
+		}
+		parts = append(parts, nth.NthDay(s.nthDay, weekday))
+	}
+	if s.monthEnd != MonthEndRollover {
+		monthEnd, ok := l.(MonthEndLocale)
+		if !ok {
+			monthEnd = english{}
+		}
+		parts = append(parts, monthEnd.MonthEnd(s.monthEnd))
+	}
+	if s.alignMode != AlignNone {
+		anchor, offset := "", ""
+		if s.alignMode == AlignAnchor {
+			anchor = describeDate(l, s.alignAnchor.In(loc))
+		}
+		if s.alignOffset != 0 {
+			offset = formatDSLDuration(s.alignOffset)
+		}
+		aligned, ok := l.(AlignedLocale)
+		if !ok {
+			aligned = english{}
+		}
+		parts = append(parts, aligned.Aligned(anchor, offset))
+	}
+	if s.hasJitter() {
+		splay, jitter := "", ""
+		if s.splayMax > 0 {
+			splay = formatDSLDuration(s.splayMax)
+		}
+		if s.jitter > 0 {
+			jitter = formatDSLDuration(s.jitter)
+		}
+		jittered, ok := l.(JitterLocale)
+		if !ok {
+			jittered = english{}
+		}
+		parts = append(parts, jittered.Jitter(splay, jitter))
+	}
+	if s.dstGap != DSTShiftForward || s.dstOverlap != DSTRunOnce {
+		dst, ok := l.(DSTLocale)
+		if !ok {
+			dst = english{}
+		}
+		if s.dstGap != DSTShiftForward {
+			parts = append(parts, dst.DSTGap(s.dstGap))
+		}
+		if s.dstOverlap != DSTRunOnce {
+			parts = append(parts, dst.DSTOverlap(s.dstOverlap))
+		}
+	}
+	if s.startDate != nil {
+		parts = append(parts, l.Starting(describeDate(l, s.startDate.In(loc))))
+	}
+	if s.endDate != nil {
+		parts = append(parts, l.Until(describeDate(l, s.endDate.In(loc))))
+	}
+	if s.maxRuns > 0 {
+		parts = append(parts, l.RunLimit(s.maxRuns, s.remainingRuns))
+	}
+	if s.calendar != nil {
+		parts = append(parts, l.Calendar())
+	}
+	if !s.precision {
+		parts = append(parts, l.Imprecise())
+	}
+
+	description := strings.Join(parts, ", ")
+	if loc != time.UTC {
+		description += " (" + loc.String() + ")"
+	}
+	switch {
+	case s.pausedUntil != nil:
+		paused, ok := l.(PausedLocale)
+		if !ok {
+			paused = english{}
+		}
+		description = paused.Paused(description, describeDate(l, s.pausedUntil.In(loc)))
+	case !s.enabled:
+		description = l.Disabled(description)
+	}
+	return description
+}
+
+// describeWindows describes time windows as "08:00–12:00 and 13:00–17:00".
+func describeWindows(l Locale, windows []TimeWindow, loc *time.Location) string {
+	items := make([]string, len(windows))
+	for i, w := range windows {
+		end := "24:00"
+		if !w.End.IsZero() {
+			end = formatDSLClock(w.End.In(loc))
+		}
+		items[i] = formatDSLClock(w.Start.In(loc)) + "–" + end
+	}
+	return l.List(items)
+}
+
+// describeWeeklyWindows describes a weekly table, grouping consecutive days
+// with the same windows, such as "Monday–Friday 09:00–18:00; Saturday
+// 10:00–14:00".
+func describeWeeklyWindows(
+	l Locale,
+	table map[time.Weekday][]TimeWindow,
+	loc *time.Location,
+) string {
+	var entries []string
+	done := make(map[time.Weekday]bool)
+	for _, day := range dslWeekdays {
+		if done[day] || len(table[day]) == 0 {
+			continue
+		}
+
+		windows := describeWindows(l, table[day], loc)
+		days := describeWeekdays(l, func(other time.Weekday) bool {
+			same := len(table[other]) > 0 && describeWindows(l, table[other], loc) == windows
+			done[other] = done[other] || same
+			return same
+		})
+		entries = append(entries, days+" "+windows)
+	}
+	return strings.Join(entries, "; ")
+}
+
+// describeWeekdays describes the weekdays matching allowed in Monday-first
+// order. Three or more consecutive days are shown as a range, such as
+// "Monday–Friday".
+func describeWeekdays(l Locale, allowed func(time.Weekday) bool) string {
+	var items []string
+	for i := 0; i < len(dslWeekdays); i++ {
+		if !allowed(dslWeekdays[i]) {
+			continue
+		}
+
+		j := i
+		for j+1 < len(dslWeekdays) && allowed(dslWeekdays[j+1]) {
+			j++
+		}
+		if j-i >= 2 {
+			items = append(items, l.Weekday(dslWeekdays[i])+"–"+l.Weekday(dslWeekdays[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, l.Weekday(dslWeekdays[k]))
+			}
+		}
+		i = j
+	}
+	return l.List(items)
+}
+
+// describeDate formats a date, adding the time of day when it is not
+// midnight.
+func describeDate(l Locale, t time.Time) string {
+	if t.Equal(startOfDay(t)) {
+		return l.Date(t)
+	}
+	return l.Date(t) + " " + formatDSLClock(t)
+}
@@ -0,0 +1,161 @@
+package robfigcronschedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Describe(t *testing.T) {
+	tests := []struct {
+		input      string
+		english    string
+		indonesian string
+	}{
+		{
+			input: "every 30m between 09:00-17:00 on mon-fri from 2025-01-06 tz=Asia/Jakarta",
+			english: "Every 30 minutes, 09:00–17:00, Monday–Friday, starting 6 Jan 2025 " +
+				"(Asia/Jakarta)",
+			indonesian: "Setiap 30 menit, 09:00–17:00, Senin–Jumat, mulai 6 Jan 2025 (Asia/Jakarta)",
+		},
+		{
+			input:      "every 1h",
+			english:    "Every hour",
+			indonesian: "Setiap jam",
+		},
+		{
+			input:   "every 15m between 08:00-12:00,22:00-02:00:30 on sat,sun",
+			english: "Every 15 minutes, 08:00–12:00 and 22:00–02:00:30, Saturday and Sunday",
+			indonesian: "Setiap 15 menit, 08:00–12:00 dan 22:00–02:00:30, " +
+				"Sabtu dan Minggu",
+		},
+		{
+			input:      "every 1h weekly mon-wed,fri=09:00-18:00;sat=10:00-24:00",
+			english:    "Every hour, Monday–Wednesday and Friday 09:00–18:00; Saturday 10:00–24:00",
+			indonesian: "Setiap jam, Senin–Rabu dan Jumat 09:00–18:00; Sabtu 10:00–24:00",
+		},
+		{
+			input: "every 1d until 2025-08-17T18:00 limit 10 remaining 4 imprecise disabled",
+			english: "Disabled: Every day, until 17 Aug 2025 18:00, at most 10 runs (4 left), " +
+				"rounded up to the interval",
+			indonesian: "Nonaktif: Setiap hari, sampai 17 Agu 2025 18:00, " +
+				"maksimal 10 kali (sisa 4), dibulatkan ke atas sesuai interval",
+		},
+		{
+			input:      "every 1h paused-until 2025-01-06T06:00",
+			english:    "Paused until 6 Jan 2025 06:00: Every hour",
+			indonesian: "Dijeda sampai 6 Jan 2025 06:00: Setiap jam",
+		},
+		{
+			input:      "every 1mo on tue nth 2",
+			english:    "Every month, on the 2nd Tuesday of the month",
+			indonesian: "Setiap bulan, pada Selasa ke-2 setiap bulan",
+		},
+		{
+			input:      "every 3mo on mon-fri nth -1",
+			english:    "Every 3 months, Monday–Friday, on the last day of the month",
+			indonesian: "Setiap 3 bulan, Senin–Jumat, pada hari terakhir setiap bulan",
+		},
+		{
+			input:      "every 1mo nth -3",
+			english:    "Every month, on the 3rd last day of the month",
+			indonesian: "Setiap bulan, pada hari ke-3 dari akhir setiap bulan",
+		},
+		{
+			input:      "every 1mo month-end clamp",
+			english:    "Every month, on the last day of shorter months",
+			indonesian: "Setiap bulan, pada hari terakhir bulan yang lebih pendek",
+		},
+		{
+			input:      "every 1y month-end skip",
+			english:    "Every year, skipping months without the day",
+			indonesian: "Setiap tahun, melewati bulan tanpa tanggal tersebut",
+		},
+		{
+			input: "every 1d between 02:30-04:00 dst-skip dst-twice tz=America/New_York",
+			english: "Every day, 02:30–04:00, skipping times lost to DST, " +
+				"twice in a repeated DST hour (America/New_York)",
+			indonesian: "Setiap hari, 02:30–04:00, melewati waktu yang hilang karena DST, " +
+				"dua kali pada jam DST yang berulang (America/New_York)",
+		},
+		{
+			input:      "every 1h align clock+7m",
+			english:    "Every hour, aligned to the clock plus 7m",
+			indonesian: "Setiap jam, selaras dengan jam ditambah 7m",
+		},
+		{
+			input:      "every 90m align 2025-01-06T09:00",
+			english:    "Every 90 minutes, aligned to 6 Jan 2025 09:00",
+			indonesian: "Setiap 90 menit, selaras dengan 6 Jan 2025 09:00",
+		},
+		{
+			input:      "every 1h jitter 30s",
+			english:    "Every hour, with up to 30s of random delay",
+			indonesian: "Setiap jam, dengan jeda acak hingga 30s",
+		},
+		{
+			input:      "every 15m splay 5m@web-1 jitter 1m",
+			english:    "Every 15 minutes, spread over 5m with up to 1m of random delay",
+			indonesian: "Setiap 15 menit, disebar dalam 5m dengan jeda acak hingga 1m",
+		},
+		{
+			input:      "every 2w limit 1",
+			english:    "Every 2 weeks, at most 1 run",
+			indonesian: "Setiap 2 minggu, maksimal 1 kali",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			s, err := Parse(tt.input)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.english, s.Describe())
+			assert.Equal(t, tt.english, s.DescribeIn(English))
+			assert.Equal(t, tt.indonesian, s.DescribeIn(Indonesian))
+		})
+	}
+}
+
+func TestSchedule_DescribeCalendar(t *testing.T) {
+	holidays := NewDateSet(time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC))
+	s, err := New(1, Day, SetCalendar(holidays))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Every day, except blocked dates", s.Describe())
+	assert.Equal(t, "Setiap hari, kecuali tanggal yang diblokir", s.DescribeIn(Indonesian))
+}
+
+// shoutingLocale is a custom Locale built on English.
+type shoutingLocale struct {
+	Locale
+}
+
+func (l shoutingLocale) Weekday(day time.Weekday) string {
+	return strings.ToUpper(l.Locale.Weekday(day))
+}
+
+func TestSchedule_DescribeCustomLocale(t *testing.T) {
+	s, err := Parse("every 5m on mon,tue")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Every 5 minutes, MONDAY and TUESDAY", s.DescribeIn(shoutingLocale{English}))
+}
+
+func TestSchedule_DescribeOptionalPhrases(t *testing.T) {
+	s, err := Parse("every 1h align clock+7m jitter 30s paused-until 2025-01-06")
+	require.NoError(t, err)
+
+	// Embedding only promotes the core Locale, so optional phrases are English
+	assert.Equal(t,
+		"Paused until 6 Jan 2025: Setiap jam, aligned to the clock plus 7m, "+
+			"with up to 30s of random delay",
+		s.DescribeIn(shoutingLocale{Indonesian}))
+	assert.Equal(t,
+		"Dijeda sampai 6 Jan 2025: Setiap jam, selaras dengan jam ditambah 7m, "+
+			"dengan jeda acak hingga 30s",
+		s.DescribeIn(Indonesian))
+}
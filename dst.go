@@ -0,0 +1,104 @@
+package robfigcronschedule
+
+import "time"
+
+// dstProbe is how far from a wall-clock time the UTC offsets in effect before
+// and after a nearby DST transition are looked up. It exceeds any UTC offset.
+const dstProbe = 15 * time.Hour
+
+// wallClock returns the instants at which a wall-clock time occurs in loc, in
+// order: one normally and two when clocks fall back and repeat it. When clocks
+// spring forward over it, none is returned and shifted is the time moved
+// forward by the length of the gap, such as 03:30 for 02:30 in New York.
+//
+// Unlike time.Date, which normalizes such times in either direction depending
+// on the zone, the result does not depend on the zone's transition rules.
+func wallClock(
+	year int, month time.Month, day, hour, min, sec, nsec int,
+	loc *time.Location,
+) (times []time.Time, shifted time.Time) {
+	wall := time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+
+	_, before := wall.Add(-dstProbe).In(loc).Zone()
+	_, after := wall.Add(dstProbe).In(loc).Zone()
+	if before == after {
+		return []time.Time{time.Date(year, month, day, hour, min, sec, nsec, loc)}, time.Time{}
+	}
+
+	for _, offset := range []int{before, after} {
+		t := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, actual := t.Zone(); actual == offset {
+			times = append(times, t)
+		}
+	}
+	if len(times) == 0 {
+		shifted = wall.Add(-time.Duration(before) * time.Second).In(loc)
+	}
+	return times, shifted
+}
+
+// atWallClock returns the wall-clock time of clock on day's date, in day's
+// location, following the DST policies: a repeated time resolves to its first
+// occurrence, and a time in a gap is shifted forward by DSTShiftForward or
+// skipped by DSTSkip, in which case ok is false.
+func (s *Schedule) atWallClock(day, clock time.Time) (t time.Time, ok bool) {
+	year, month, date := day.Date()
+	times, shifted := wallClock(year, month, date,
+		clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), day.Location())
+	if len(times) > 0 {
+		return times[0], true
+	}
+	return shifted, s.dstGap != DSTSkip
+}
+
+// repeatOf returns the second occurrence of t's wall-clock time when t is the
+// first occurrence of a time repeated by a fall-back transition and the
+// overlap policy is DSTRunTwice.
+func (s *Schedule) repeatOf(t time.Time) (time.Time, bool) {
+	if s.dstOverlap != DSTRunTwice {
+		return time.Time{}, false
+	}
+
+	year, month, day := t.Date()
+	times, _ := wallClock(year, month, day,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if len(times) == 2 && times[0].Equal(t) {
+		return times[1], true
+	}
+	return time.Time{}, false
+}
+
+// addDate adds years, months and days to t like time.AddDate, but keeps t's
+// wall-clock time: a repeated time resolves to its first occurrence and a time
+// in a gap is shifted forward, whatever the zone's transition rules.
+func addDate(t time.Time, years, months, days int) time.Time {
+	year, month, day := t.Date()
+	return firstAt(year+years, month+time.Month(months), day+days,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// startOfDay returns the first instant of t's date in t's location: midnight,
+// or the end of the gap when clocks spring forward over midnight.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return firstAt(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfNextDay returns the first instant of the date after t's.
+func startOfNextDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return firstAt(year, month, day+1, 0, 0, 0, 0, t.Location())
+}
+
+// firstAt returns the first instant at which a wall-clock time occurs in loc,
+// or the time shifted forward out of a gap.
+func firstAt(
+	year int, month time.Month, day, hour, min, sec, nsec int,
+	loc *time.Location,
+) time.Time {
+	times, shifted := wallClock(year, month, day, hour, min, sec, nsec, loc)
+	if len(times) > 0 {
+		return times[0]
+	}
+	return shifted
+}
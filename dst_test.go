@@ -0,0 +1,262 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zonedLayout shows the UTC offset, which tells apart the two occurrences of a
+// wall-clock time repeated by a DST transition.
+const zonedLayout = "2006-01-02 15:04:05 -0700"
+
+// parseZoned parses a time in zonedLayout and returns it in loc.
+func parseZoned(t *testing.T, loc *time.Location, timeStr string) time.Time {
+	parsed, err := time.Parse(zonedLayout, timeStr)
+	require.NoError(t, err)
+	return parsed.In(loc)
+}
+
+// formatZoned formats each time in zonedLayout.
+func formatZoned(times ...time.Time) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format(zonedLayout)
+	}
+	return formatted
+}
+
+func loadDSTZones(t *testing.T) (newYork, berlin *time.Location) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	berlin, err = time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	return newYork, berlin
+}
+
+func TestSchedule_DSTDayStepping(t *testing.T) {
+	newYork, berlin := loadDSTZones(t)
+
+	// The spring-forward day is 23 hours long, so stepping 24 hours from late
+	// Saturday evening would land on Monday and skip Sunday
+	schedule, err := New(1, Hour, SetAllowedWeekdays(time.Sunday))
+	require.NoError(t, err)
+
+	next := schedule.Next(parseZoned(t, newYork, "2025-03-08 23:30:00 -0500"))
+	assert.Equal(t, "2025-03-09 00:00:00 -0500", next.Format(zonedLayout))
+
+	next = schedule.Next(parseZoned(t, berlin, "2025-03-29 23:30:00 +0100"))
+	assert.Equal(t, "2025-03-30 00:00:00 +0100", next.Format(zonedLayout))
+
+	// Daily runs stay at midnight across both transitions
+	schedule, err = New(1, Day)
+	require.NoError(t, err)
+
+	runs := schedule.NextN(parseZoned(t, newYork, "2025-03-08 00:00:00 -0500"), 2)
+	assert.Equal(t, []string{
+		"2025-03-09 00:00:00 -0500",
+		"2025-03-10 00:00:00 -0400",
+	}, formatZoned(runs...))
+
+	runs = schedule.NextN(parseZoned(t, berlin, "2025-10-25 00:00:00 +0200"), 2)
+	assert.Equal(t, []string{
+		"2025-10-26 00:00:00 +0200",
+		"2025-10-27 00:00:00 +0100",
+	}, formatZoned(runs...))
+
+	// Hourly runs count elapsed time and pass through the repeated hour
+	schedule, err = New(1, Hour, SetDSTPolicy(DSTSkip, DSTRunTwice))
+	require.NoError(t, err)
+
+	runs = schedule.NextN(parseZoned(t, newYork, "2025-11-02 00:30:00 -0400"), 3)
+	assert.Equal(t, []string{
+		"2025-11-02 01:30:00 -0400",
+		"2025-11-02 01:30:00 -0500",
+		"2025-11-02 02:30:00 -0500",
+	}, formatZoned(runs...))
+}
+
+func TestSchedule_DSTGap(t *testing.T) {
+	newYork, berlin := loadDSTZones(t)
+
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		gap      DSTGapPolicy
+		end      time.Duration
+		from     string
+		expected []string
+	}{
+		{
+			name: "window opens in the gap, shift forward",
+			loc:  newYork,
+			gap:  DSTShiftForward,
+			from: "2025-03-09 00:00:00 -0500",
+			expected: []string{
+				"2025-03-09 03:30:00 -0400",
+				"2025-03-10 02:30:00 -0400",
+			},
+		},
+		{
+			name: "window inside the gap, shift forward",
+			loc:  newYork,
+			gap:  DSTShiftForward,
+			end:  3 * time.Hour,
+			from: "2025-03-09 00:00:00 -0500",
+			expected: []string{
+				"2025-03-09 03:30:00 -0400",
+				"2025-03-10 02:30:00 -0400",
+			},
+		},
+		{
+			name: "window inside the gap, skip",
+			loc:  newYork,
+			gap:  DSTSkip,
+			end:  3 * time.Hour,
+			from: "2025-03-09 00:00:00 -0500",
+			expected: []string{
+				"2025-03-10 02:30:00 -0400",
+				"2025-03-11 02:30:00 -0400",
+			},
+		},
+		{
+			name: "window opens in the gap, skip",
+			loc:  newYork,
+			gap:  DSTSkip,
+			from: "2025-03-09 00:00:00 -0500",
+			expected: []string{
+				"2025-03-10 02:30:00 -0400",
+				"2025-03-11 02:30:00 -0400",
+			},
+		},
+		{
+			name: "Berlin, shift forward",
+			loc:  berlin,
+			gap:  DSTShiftForward,
+			from: "2025-03-30 00:00:00 +0100",
+			expected: []string{
+				"2025-03-30 03:30:00 +0200",
+				"2025-03-31 02:30:00 +0200",
+			},
+		},
+		{
+			name: "Berlin, skip",
+			loc:  berlin,
+			gap:  DSTSkip,
+			from: "2025-03-30 00:00:00 +0100",
+			expected: []string{
+				"2025-03-31 02:30:00 +0200",
+				"2025-04-01 02:30:00 +0200",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := 4 * time.Hour
+			if tt.end != 0 {
+				end = tt.end
+			}
+			schedule, err := New(1, Day,
+				SetTimeWindows(TimeWindow{
+					Start: time.Date(2000, 1, 1, 2, 30, 0, 0, tt.loc),
+					End:   time.Date(2000, 1, 1, 0, 0, 0, 0, tt.loc).Add(end),
+				}),
+				SetDSTPolicy(tt.gap, DSTRunOnce),
+			)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(parseZoned(t, tt.loc, tt.from), len(tt.expected))
+			assert.Equal(t, tt.expected, formatZoned(runs...))
+		})
+	}
+}
+
+func TestSchedule_DSTOverlap(t *testing.T) {
+	newYork, berlin := loadDSTZones(t)
+
+	tests := []struct {
+		name     string
+		loc      *time.Location
+		overlap  DSTOverlapPolicy
+		start    time.Time
+		from     string
+		expected []string
+	}{
+		{
+			name:    "run once",
+			loc:     newYork,
+			overlap: DSTRunOnce,
+			start:   time.Date(2000, 1, 1, 1, 30, 0, 0, newYork),
+			from:    "2025-11-02 00:00:00 -0400",
+			expected: []string{
+				"2025-11-02 01:30:00 -0400",
+				"2025-11-03 01:30:00 -0500",
+			},
+		},
+		{
+			name:    "run twice",
+			loc:     newYork,
+			overlap: DSTRunTwice,
+			start:   time.Date(2000, 1, 1, 1, 30, 0, 0, newYork),
+			from:    "2025-11-02 00:00:00 -0400",
+			expected: []string{
+				"2025-11-02 01:30:00 -0400",
+				"2025-11-02 01:30:00 -0500",
+				"2025-11-03 01:30:00 -0500",
+			},
+		},
+		{
+			name:    "Berlin, run once",
+			loc:     berlin,
+			overlap: DSTRunOnce,
+			start:   time.Date(2000, 1, 1, 2, 30, 0, 0, berlin),
+			from:    "2025-10-26 00:00:00 +0200",
+			expected: []string{
+				"2025-10-26 02:30:00 +0200",
+				"2025-10-27 02:30:00 +0100",
+			},
+		},
+		{
+			name:    "Berlin, run twice",
+			loc:     berlin,
+			overlap: DSTRunTwice,
+			start:   time.Date(2000, 1, 1, 2, 30, 0, 0, berlin),
+			from:    "2025-10-26 00:00:00 +0200",
+			expected: []string{
+				"2025-10-26 02:30:00 +0200",
+				"2025-10-26 02:30:00 +0100",
+				"2025-10-27 02:30:00 +0100",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(1, Day,
+				SetTimeWindows(TimeWindow{Start: tt.start, End: tt.start.Add(time.Hour)}),
+				SetDSTPolicy(DSTShiftForward, tt.overlap),
+			)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(parseZoned(t, tt.loc, tt.from), len(tt.expected))
+			assert.Equal(t, tt.expected, formatZoned(runs...))
+
+			// Prev walks the same runs backwards
+			for i := 1; i < len(runs); i++ {
+				assert.Equal(t, runs[i-1].Format(zonedLayout),
+					schedule.Prev(runs[i]).Format(zonedLayout))
+			}
+		})
+	}
+}
+
+func TestSchedule_DSTPolicyValidation(t *testing.T) {
+	_, err := New(1, Hour, SetDSTPolicy(DSTGapPolicy(2), DSTRunOnce))
+	assert.ErrorIs(t, err, ErrInvalidDSTPolicy)
+
+	_, err = New(1, Hour, SetDSTPolicy(DSTShiftForward, DSTOverlapPolicy(-1)))
+	assert.ErrorIs(t, err, ErrInvalidDSTPolicy)
+}
@@ -0,0 +1,392 @@
+package robfigcronschedule
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405"
+)
+
+// ICSCalendar is a RangeCalendar built from the VEVENTs of an iCalendar
+// (RFC 5545) file, such as a published holiday calendar.
+// All-day events block whole dates, timed events block the time range between
+// their start and end. Recurring events (RRULE) are expanded and EXDATE
+// exclusions are honored. Cancelled events are ignored.
+type ICSCalendar struct {
+	events []icsEvent
+}
+
+// icsEvent is a single VEVENT. All-day events keep their start as midnight UTC
+// of the civil date and their length in days; timed events keep their start
+// instant and duration.
+type icsEvent struct {
+	start    time.Time
+	allDay   bool
+	days     int
+	duration time.Duration
+	rule     *rrule
+	exdates  []time.Time
+}
+
+// ReadICS parses an iCalendar stream and returns the calendar of its events.
+// Floating date-times (without a zone or TZID) are interpreted in time.Local.
+//
+// Returns ErrInvalidICS for malformed input and *UnsupportedRRuleError for
+// recurrence rules that cannot be expanded.
+func ReadICS(r io.Reader) (*ICSCalendar, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ICSCalendar{}
+	var current *icsProperties
+	depth := 0
+
+	for _, line := range lines {
+		name, params, value, err := parseICSProperty(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidICS, line.number, err)
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && current == nil:
+			current = &icsProperties{}
+		case current == nil:
+			// Outside of a VEVENT
+		case name == "BEGIN":
+			depth++
+		case name == "END" && depth > 0:
+			depth--
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			event, ok, err := current.event()
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidICS, line.number, err)
+			}
+			if ok {
+				calendar.events = append(calendar.events, event)
+			}
+			current = nil
+		case depth == 0:
+			current.add(name, params, value)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidICS)
+	}
+
+	return calendar, nil
+}
+
+// LoadICSFile parses the iCalendar file at path.
+// See ReadICS for details.
+//
+// Example:
+//
+//	holidays, err := LoadICSFile("holidays.ics")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	New(1, Day, SetCalendar(holidays))
+func LoadICSFile(path string) (*ICSCalendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadICS(f)
+}
+
+// Len returns the number of events in the calendar. A recurring event counts
+// once.
+func (c *ICSCalendar) Len() int {
+	return len(c.events)
+}
+
+// IsBlocked reports whether an all-day event covers date's calendar date.
+func (c *ICSCalendar) IsBlocked(date time.Time) bool {
+	year, month, day := date.Date()
+	target := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	for _, event := range c.events {
+		if !event.allDay {
+			continue
+		}
+
+		blocked := false
+		event.occurrences(target, func(start time.Time) bool {
+			blocked = target.Before(start.AddDate(0, 0, event.days))
+			return !blocked
+		})
+		if blocked {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockedUntil reports whether a timed event covers t and, if so, when the
+// latest such event ends.
+func (c *ICSCalendar) BlockedUntil(t time.Time) (time.Time, bool) {
+	var until time.Time
+	for _, event := range c.events {
+		if event.allDay {
+			continue
+		}
+
+		event.occurrences(t, func(start time.Time) bool {
+			if end := start.Add(event.duration); t.Before(end) && end.After(until) {
+				until = end
+			}
+			return true
+		})
+	}
+	return until, !until.IsZero()
+}
+
+// occurrences calls fn with the start of each non-excluded occurrence that
+// starts at or before limit, in order, until fn returns false.
+func (e icsEvent) occurrences(limit time.Time, fn func(time.Time) bool) {
+	visit := func(start time.Time) bool {
+		if start.After(limit) {
+			return false
+		}
+		for _, exdate := range e.exdates {
+			if exdate.Equal(start) {
+				return true
+			}
+		}
+		return fn(start)
+	}
+
+	if e.rule == nil {
+		visit(e.start)
+		return
+	}
+	e.rule.occurrences(e.start, visit)
+}
+
+// icsProperties collects the VEVENT properties needed to build an icsEvent.
+type icsProperties struct {
+	dtstart, dtend, duration, rrule *icsProperty
+	exdates                         []*icsProperty
+	cancelled                       bool
+}
+
+// icsProperty is a property value with its parameters.
+type icsProperty struct {
+	params map[string]string
+	value  string
+}
+
+func (p *icsProperties) add(name string, params map[string]string, value string) {
+	prop := &icsProperty{params: params, value: value}
+	switch name {
+	case "DTSTART":
+		p.dtstart = prop
+	case "DTEND":
+		p.dtend = prop
+	case "DURATION":
+		p.duration = prop
+	case "RRULE":
+		p.rrule = prop
+	case "EXDATE":
+		p.exdates = append(p.exdates, prop)
+	case "STATUS":
+		p.cancelled = strings.EqualFold(value, "CANCELLED")
+	}
+}
+
+// event builds the icsEvent. ok is false for events that block nothing:
+// cancelled events and timed events without a length.
+func (p *icsProperties) event() (event icsEvent, ok bool, err error) {
+	if p.dtstart == nil {
+		return event, false, fmt.Errorf("VEVENT without DTSTART")
+	}
+	if p.cancelled {
+		return event, false, nil
+	}
+
+	event.start, event.allDay, err = p.dtstart.time()
+	if err != nil {
+		return event, false, err
+	}
+
+	switch {
+	case p.dtend != nil:
+		end, _, err := p.dtend.time()
+		if err != nil {
+			return event, false, err
+		}
+		event.duration = end.Sub(event.start)
+	case p.duration != nil:
+		event.duration, err = parseICSDuration(p.duration.value)
+		if err != nil {
+			return event, false, err
+		}
+	case event.allDay:
+		event.duration = 24 * time.Hour
+	}
+
+	if event.allDay {
+		event.days = int(event.duration / (24 * time.Hour))
+		if event.days < 1 {
+			event.days = 1
+		}
+	} else if event.duration <= 0 {
+		return event, false, nil
+	}
+
+	if p.rrule != nil {
+		event.rule, err = parseRRule(p.rrule.value, event.start.Location())
+		if err != nil {
+			return event, false, err
+		}
+	}
+
+	for _, exdate := range p.exdates {
+		for _, value := range strings.Split(exdate.value, ",") {
+			t, _, err := parseICSTime(value, exdate.params["TZID"], time.Local)
+			if err != nil {
+				return event, false, err
+			}
+			event.exdates = append(event.exdates, t)
+		}
+	}
+
+	return event, true, nil
+}
+
+// time parses the property as a DATE or DATE-TIME value.
+func (p *icsProperty) time() (time.Time, bool, error) {
+	if strings.EqualFold(p.params["VALUE"], "DATE") && len(p.value) != len(icsDateLayout) {
+		return time.Time{}, false, fmt.Errorf("invalid date %q", p.value)
+	}
+	return parseICSTime(p.value, p.params["TZID"], time.Local)
+}
+
+// parseICSTime parses an iCalendar DATE (20060102) or DATE-TIME
+// (20060102T150405, optionally with a trailing Z for UTC) value. Dates are
+// returned as midnight UTC with allDay set. Date-times use tzid if given and
+// loc otherwise.
+func parseICSTime(value, tzid string, loc *time.Location) (t time.Time, allDay bool, err error) {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case len(value) == len(icsDateLayout):
+		t, err = time.ParseInLocation(icsDateLayout, value, time.UTC)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.ParseInLocation(icsDateTimeLayout, strings.TrimSuffix(value, "Z"), time.UTC)
+		return t, false, err
+	case tzid != "":
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+
+	t, err = time.ParseInLocation(icsDateTimeLayout, value, loc)
+	return t, false, err
+}
+
+var icsDurationPattern = regexp.MustCompile(
+	`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`,
+)
+
+// parseICSDuration parses an iCalendar DURATION value such as P1D or PT1H30M.
+func parseICSDuration(value string) (time.Duration, error) {
+	match := icsDurationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		duration += time.Duration(n) * unit
+	}
+
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+// icsLine is an unfolded content line with the number of its first physical line.
+type icsLine struct {
+	number int
+	text   string
+}
+
+// unfoldICSLines reads content lines, joining folded continuation lines that
+// start with a space or tab.
+func unfoldICSLines(r io.Reader) ([]icsLine, error) {
+	var lines []icsLine
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case text == "":
+			continue
+		case (text[0] == ' ' || text[0] == '\t') && len(lines) > 0:
+			lines[len(lines)-1].text += text[1:]
+		default:
+			lines = append(lines, icsLine{number: number, text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// parseICSProperty splits a content line of the form
+// NAME;PARAM=VALUE;PARAM="QUOTED":VALUE into its parts. Names and parameter
+// names are upper-cased.
+func parseICSProperty(
+	line string,
+) (name string, params map[string]string, value string, err error) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", fmt.Errorf("missing ':' in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	name = strings.ToUpper(parts[0])
+	params = make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return name, params, line[colon+1:], nil
+}
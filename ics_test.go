@@ -0,0 +1,227 @@
+package robfigcronschedule
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//HR//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Christmas Day\r\n" +
+	"DTSTART;VALUE=DATE:20241225\r\n" +
+	"DTEND;VALUE=DATE:20241226\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Year-end freeze spanning\r\n" +
+	"  two days\r\n" +
+	"DTSTART;VALUE=DATE:20241230\r\n" +
+	"DTEND;VALUE=DATE:20250101\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:New Year's Day\r\n" +
+	"DTSTART;VALUE=DATE:20200101\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"DURATION:PT5M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Monthly maintenance\r\n" +
+	"DTSTART;TZID=Asia/Jakarta:20240109T100000\r\n" +
+	"DURATION:PT2H\r\n" +
+	"RRULE:FREQ=MONTHLY;BYDAY=2TU\r\n" +
+	"EXDATE;TZID=Asia/Jakarta:20240213T100000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Cancelled offsite\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART;VALUE=DATE:20240311\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestReadICS_BlockedDates(t *testing.T) {
+	calendar, err := ReadICS(strings.NewReader(testICS))
+	require.NoError(t, err)
+	assert.Equal(t, 4, calendar.Len())
+
+	tests := []struct {
+		date    string
+		blocked bool
+	}{
+		{date: "2024-12-24 12:00:00", blocked: false},
+		{date: "2024-12-25 12:00:00", blocked: true},
+		{date: "2024-12-30 12:00:00", blocked: true},
+		{date: "2024-12-31 12:00:00", blocked: true},
+		{date: "2025-01-01 12:00:00", blocked: true}, // recurring New Year
+		{date: "2030-01-01 12:00:00", blocked: true},
+		{date: "2019-01-01 12:00:00", blocked: false}, // before DTSTART
+		{date: "2024-03-11 12:00:00", blocked: false}, // cancelled
+		{date: "2024-01-09 12:00:00", blocked: false}, // timed event
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			assert.Equal(t, tt.blocked, calendar.IsBlocked(parseTime(t, tt.date)))
+		})
+	}
+}
+
+func TestReadICS_BlockedRanges(t *testing.T) {
+	calendar, err := ReadICS(strings.NewReader(testICS))
+	require.NoError(t, err)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	// Second Tuesday of March 2024, 10:00-12:00 Jakarta
+	until, blocked := calendar.BlockedUntil(time.Date(2024, 3, 12, 11, 0, 0, 0, jakarta))
+	assert.True(t, blocked)
+	assert.True(t, time.Date(2024, 3, 12, 12, 0, 0, 0, jakarta).Equal(until))
+
+	// End is exclusive
+	_, blocked = calendar.BlockedUntil(time.Date(2024, 3, 12, 12, 0, 0, 0, jakarta))
+	assert.False(t, blocked)
+
+	// February occurrence is excluded
+	_, blocked = calendar.BlockedUntil(time.Date(2024, 2, 13, 11, 0, 0, 0, jakarta))
+	assert.False(t, blocked)
+}
+
+func TestReadICS_UnsortedMonths(t *testing.T) {
+	// Occurrences must come in order for UNTIL, COUNT and the lookup limit
+	for _, byMonth := range []string{"1,12", "12,1"} {
+		t.Run(byMonth, func(t *testing.T) {
+			calendar, err := ReadICS(strings.NewReader("BEGIN:VEVENT\n" +
+				"DTSTART;VALUE=DATE:20241201\n" +
+				"RRULE:FREQ=YEARLY;BYMONTH=" + byMonth + ";BYMONTHDAY=1\n" +
+				"END:VEVENT\n"))
+			require.NoError(t, err)
+
+			assert.True(t, calendar.IsBlocked(parseTime(t, "2024-12-01 12:00:00")))
+			assert.True(t, calendar.IsBlocked(parseTime(t, "2025-01-01 12:00:00")))
+			assert.True(t, calendar.IsBlocked(parseTime(t, "2025-12-01 12:00:00")))
+			assert.False(t, calendar.IsBlocked(parseTime(t, "2025-02-01 12:00:00")))
+		})
+	}
+}
+
+func TestReadICS_YearlyWithoutMonths(t *testing.T) {
+	tests := []struct {
+		rule    string
+		blocked []string
+		free    []string
+	}{
+		{
+			rule:    "FREQ=YEARLY;BYMONTHDAY=15",
+			blocked: []string{"2025-01-15 12:00:00", "2025-02-15 12:00:00", "2025-12-15 12:00:00"},
+			free:    []string{"2025-02-14 12:00:00", "2025-02-16 12:00:00"},
+		},
+		{
+			rule:    "FREQ=YEARLY;BYDAY=MO",
+			blocked: []string{"2025-01-06 12:00:00", "2025-02-03 12:00:00", "2025-12-29 12:00:00"},
+			free:    []string{"2025-02-04 12:00:00", "2025-12-28 12:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			calendar, err := ReadICS(strings.NewReader("BEGIN:VEVENT\n" +
+				"DTSTART;VALUE=DATE:20250106\n" +
+				"RRULE:" + tt.rule + "\n" +
+				"END:VEVENT\n"))
+			require.NoError(t, err)
+
+			for _, date := range tt.blocked {
+				assert.True(t, calendar.IsBlocked(parseTime(t, date)), date)
+			}
+			for _, date := range tt.free {
+				assert.False(t, calendar.IsBlocked(parseTime(t, date)), date)
+			}
+		})
+	}
+}
+
+func TestReadICS_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		unsupported bool
+	}{
+		{
+			name:  "unterminated event",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20241225\n",
+		},
+		{
+			name:  "missing dtstart",
+			input: "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n",
+		},
+		{
+			name:  "malformed line",
+			input: "BEGIN:VEVENT\nDTSTART\nEND:VEVENT\n",
+		},
+		{
+			name:  "invalid date",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2024-12-25\nEND:VEVENT\n",
+		},
+		{
+			name: "unsupported rule",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20241225\n" +
+				"RRULE:FREQ=YEARLY;BYWEEKNO=1\nEND:VEVENT\n",
+			unsupported: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadICS(strings.NewReader(tt.input))
+			assert.ErrorIs(t, err, ErrInvalidICS)
+
+			var unsupported *UnsupportedRRuleError
+			assert.Equal(t, tt.unsupported, errors.As(err, &unsupported))
+		})
+	}
+}
+
+func TestLoadICSFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	require.NoError(t, os.WriteFile(path, []byte(testICS), 0o600))
+
+	calendar, err := LoadICSFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 4, calendar.Len())
+}
+
+func TestSchedule_ICSCalendar(t *testing.T) {
+	calendar, err := ReadICS(strings.NewReader(testICS))
+	require.NoError(t, err)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	startTime := time.Date(2000, 1, 1, 9, 0, 0, 0, jakarta)
+	endTime := time.Date(2000, 1, 1, 17, 0, 0, 0, jakarta)
+	schedule, err := New(30, Minute,
+		SetStartTime(&startTime),
+		SetEndTime(&endTime),
+		SetCalendar(calendar),
+	)
+	require.NoError(t, err)
+
+	// Run inside the maintenance range resumes when it ends
+	next := schedule.Next(time.Date(2024, 3, 12, 9, 45, 0, 0, jakarta))
+	assert.True(t, time.Date(2024, 3, 12, 12, 30, 0, 0, jakarta).Equal(next), next)
+
+	// Holiday is skipped entirely
+	next = schedule.Next(time.Date(2024, 12, 24, 16, 45, 0, 0, jakarta))
+	assert.True(t, time.Date(2024, 12, 26, 9, 0, 0, 0, jakarta).Equal(next), next)
+}
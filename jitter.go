@@ -0,0 +1,158 @@
+package robfigcronschedule
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// hasJitter reports whether runs are delayed by SetJitter or SetSplay.
+func (s *Schedule) hasJitter() bool {
+	return s.jitter > 0 || s.splayMax > 0
+}
+
+// jitterFrom returns the time to compute the next run from: t, or t moved
+// back by the delay of the last delayed run once t reaches that run, so
+// runs keep following the undelayed ones instead of drifting by the delay.
+func (s *Schedule) jitterFrom(t time.Time) time.Time {
+	if !s.hasJitter() || s.jitterRun.IsZero() || t.Before(s.jitterRun) {
+		return t
+	}
+	return t.Add(-s.jitterRun.Sub(s.jitterBase))
+}
+
+// withJitter delays run, computed from jitterFrom(t), by delayRun. If the
+// delayed run is not after t because t is late, the next run after t is
+// delayed instead.
+func (s *Schedule) withJitter(run, t time.Time) time.Time {
+	if !s.hasJitter() || run.IsZero() {
+		return run
+	}
+
+	delayed := s.delayRun(run)
+	if delayed.After(t) {
+		return delayed
+	}
+	s.trace.reject(delayed, RuleJitter, RuleJitter, "not after %s", formatTraceTime(t))
+	if run = s.next(t); run.IsZero() {
+		return run
+	}
+	return s.delayRun(run)
+}
+
+// delayRun returns run delayed by the splay plus a random jitter. When less
+// room than the largest delay is left (see jitterRoom), the delay is scaled
+// down to fit, keeping runs spread out. The delay is remembered, so a run
+// computed again is delayed the same way.
+func (s *Schedule) delayRun(run time.Time) time.Time {
+	s.trace.step(7, RuleJitter, "delay by up to %s", s.splayMax+s.jitter)
+	if !s.jitterRun.IsZero() && run.Equal(s.jitterBase) {
+		s.trace.propose(s.jitterRun, RuleJitter, "delayed like before")
+		return s.jitterRun.In(run.Location())
+	}
+
+	delay := s.splay
+	if s.jitter > 0 && s.trace != nil {
+		// Explain has no side effects, so it leaves out the random delay
+		// instead of drawing from the random source
+		s.trace.step(7, RuleJitter, "random delay of up to %s not drawn", s.jitter)
+	} else if s.jitter > 0 {
+		delay += time.Duration(s.randInt63n(int64(s.jitter)))
+	}
+	room := s.jitterRoom(run)
+	if total := s.splayMax + s.jitter; total > room {
+		delay = time.Duration(float64(delay) * float64(room) / float64(total))
+	}
+
+	delayed := run.Add(delay)
+	if ranges, ok := s.calendar.(RangeCalendar); ok {
+		if _, blocked := ranges.BlockedUntil(delayed); blocked {
+			s.trace.reject(delayed, RuleJitter, RuleCalendar, "blocked, run without delay")
+			delayed = run
+		}
+	}
+
+	s.trace.propose(delayed, RuleJitter, "delayed by %s", delayed.Sub(run))
+	s.jitterBase, s.jitterRun = run, delayed
+	return delayed
+}
+
+// jitterRoom returns how far run may be delayed: until the end of its time
+// window, or of its day without a window, but before the next step of a
+// Second, Minute or Hour interval and not past the end date.
+func (s *Schedule) jitterRoom(run time.Time) time.Duration {
+	limit := endOfDay(run)
+	if s.hasWindows() {
+		limit = run
+		today := startOfDay(run)
+		for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+			if !s.isDayAllowed(day) {
+				continue
+			}
+			for _, w := range s.windowsOn(day) {
+				if !run.Before(w.open) && !run.After(w.end) {
+					limit = w.end
+				}
+			}
+		}
+	}
+
+	if step := s.fixedInterval(); step > 0 && run.Add(step-1).Before(limit) {
+		limit = run.Add(step - 1)
+	}
+	if s.endDate != nil && s.endDate.Before(limit) {
+		limit = *s.endDate
+	}
+	if limit.Before(run) {
+		return 0
+	}
+	return limit.Sub(run)
+}
+
+// randInt63n returns a random number in [0, n) from the source set with
+// SetJitterSource, or from math/rand.
+func (s *Schedule) randInt63n(n int64) int64 {
+	if s.jitterRand != nil {
+		return s.jitterRand.Int63n(n)
+	}
+	return rand.Int63n(n)
+}
+
+// lockedSource is a random source guarded by its own lock, as one source
+// passed to SetJitterSource may be used by several schedules.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (l *lockedSource) Int63() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.src.Int63()
+}
+
+func (l *lockedSource) Seed(seed int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.src.Seed(seed)
+}
+
+// splayOffset returns the fixed delay of key in [0, maxDelay), the same for
+// every instance using the same key.
+func splayOffset(key string, maxDelay time.Duration) time.Duration {
+	if maxDelay <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return time.Duration(h.Sum64() % uint64(maxDelay))
+}
+
+// validateJitter checks that the jitter and splay are not negative.
+func validateJitter(s *Schedule) error {
+	if s.jitter < 0 || s.splayMax < 0 {
+		return ErrInvalidJitter
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package robfigcronschedule
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runsLikeCron calls Next n times, each time with the previous run, as
+// robfig/cron does.
+func runsLikeCron(s *Schedule, from time.Time, n int) []time.Time {
+	runs := make([]time.Time, 0, n)
+	for t := from; len(runs) < n; {
+		next := s.Next(t)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+		t = next
+	}
+	return runs
+}
+
+func TestSchedule_JitterBounds(t *testing.T) {
+	weekdays := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+	}
+
+	tests := []struct {
+		name     string
+		interval int
+		unit     IntervalTimeUnit
+		opts     []ScheduleOption
+		jitter   time.Duration
+		// within reports whether a delayed run stays where its run was allowed
+		within func(run, delayed time.Time) bool
+	}{
+		{
+			name:     "inside the time window",
+			interval: 1,
+			unit:     Hour,
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: clock(9, 0), End: clock(17, 0)}),
+			},
+			jitter: 30 * time.Minute,
+			within: func(run, delayed time.Time) bool {
+				return delayed.Sub(startOfDay(run)) <= 17*time.Hour
+			},
+		},
+		{
+			name:     "scaled down near the window end",
+			interval: 15,
+			unit:     Minute,
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: clock(9, 0), End: clock(9, 20)}),
+			},
+			jitter: time.Hour,
+			within: func(run, delayed time.Time) bool {
+				return delayed.Sub(run) < 15*time.Minute &&
+					delayed.Sub(startOfDay(run)) <= 9*time.Hour+20*time.Minute
+			},
+		},
+		{
+			name:     "on the same allowed day",
+			interval: 1,
+			unit:     Day,
+			opts:     []ScheduleOption{SetAllowedWeekdays(weekdays...)},
+			jitter:   72 * time.Hour,
+			within: func(run, delayed time.Time) bool {
+				return startOfDay(delayed).Equal(startOfDay(run))
+			},
+		},
+		{
+			name:     "before the next step",
+			interval: 10,
+			unit:     Second,
+			jitter:   time.Minute,
+			within: func(run, delayed time.Time) bool {
+				return delayed.Sub(run) < 10*time.Second
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, err := New(tt.interval, tt.unit, tt.opts...)
+			require.NoError(t, err)
+			jittered, err := New(tt.interval, tt.unit, append(tt.opts,
+				SetJitter(tt.jitter), SetJitterSource(rand.NewSource(1)))...)
+			require.NoError(t, err)
+
+			from := parseTime(t, "2025-01-06 08:00:00")
+			runs := plain.NextN(from, 100)
+			delayed := runsLikeCron(jittered, from, 100)
+			require.Len(t, delayed, len(runs))
+
+			// Runs keep following the undelayed ones instead of drifting
+			moved := 0
+			for i, run := range runs {
+				assert.False(t, delayed[i].Before(run), "run %d", i)
+				assert.True(t, tt.within(run, delayed[i]), "run %d: %s -> %s", i, run, delayed[i])
+				assert.True(t, jittered.isDayAllowed(delayed[i]))
+				if !delayed[i].Equal(run) {
+					moved++
+				}
+			}
+			assert.Greater(t, moved, len(runs)/2)
+		})
+	}
+}
+
+func TestSchedule_JitterSource(t *testing.T) {
+	newSchedule := func(seed int64) *Schedule {
+		s, err := New(1, Hour, SetJitter(10*time.Minute), SetJitterSource(rand.NewSource(seed)))
+		require.NoError(t, err)
+		return s
+	}
+
+	from := parseTime(t, "2025-01-06 08:00:00")
+	first := runsLikeCron(newSchedule(7), from, 20)
+	assert.Equal(t, first, runsLikeCron(newSchedule(7), from, 20))
+	assert.NotEqual(t, first, runsLikeCron(newSchedule(8), from, 20))
+}
+
+func TestSchedule_JitterExplain(t *testing.T) {
+	newSchedule := func() *Schedule {
+		s, err := New(1, Hour, SetJitter(10*time.Minute), SetJitterSource(rand.NewSource(7)))
+		require.NoError(t, err)
+		return s
+	}
+	from := parseTime(t, "2025-01-06 08:00:00")
+
+	// Explain reports the run without drawing its delay
+	explained := newSchedule()
+	e := explained.Explain(from)
+	assert.Equal(t, parseTime(t, "2025-01-06 09:00:00"), e.Next)
+	assert.Contains(t, e.String(), "random delay of up to 10m0s not drawn")
+	assert.Equal(t, runsLikeCron(newSchedule(), from, 20), runsLikeCron(explained, from, 20))
+
+	// A delay Next already drew is reported as is
+	next := explained.Next(from)
+	assert.Equal(t, next, explained.Explain(from).Next)
+	assert.Equal(t, next, explained.Next(from))
+}
+
+func TestSchedule_JitterSourceShared(t *testing.T) {
+	// One option value on several schedules shares its source safely
+	option := SetJitterSource(rand.NewSource(1))
+	var schedules []*Schedule
+	for i := 0; i < 4; i++ {
+		s, err := New(1, Minute, SetJitter(30*time.Second), option)
+		require.NoError(t, err)
+		schedules = append(schedules, s)
+	}
+
+	done := make(chan struct{})
+	for _, s := range schedules {
+		go func(s *Schedule) {
+			defer func() { done <- struct{}{} }()
+			runsLikeCron(s, parseTime(t, "2025-01-06 08:00:00"), 100)
+		}(s)
+	}
+	for range schedules {
+		<-done
+	}
+}
+
+func TestSchedule_Splay(t *testing.T) {
+	web1, web2 := splayOffset("web-1", 10*time.Minute), splayOffset("web-2", 10*time.Minute)
+	assert.NotEqual(t, web1, web2)
+	assert.Less(t, web1, 10*time.Minute)
+
+	s, err := New(1, Hour, AlignToClock(0), SetSplay("web-1", 10*time.Minute))
+	require.NoError(t, err)
+
+	runs := runsLikeCron(s, parseTime(t, "2025-01-06 08:30:00"), 3)
+	assert.Equal(t, []time.Time{
+		parseTime(t, "2025-01-06 09:00:00").Add(web1),
+		parseTime(t, "2025-01-06 10:00:00").Add(web1),
+		parseTime(t, "2025-01-06 11:00:00").Add(web1),
+	}, runs)
+
+	// Previews leave out the delay, even of the cached run
+	assert.Equal(t, parseTime(t, "2025-01-06 12:00:00"), s.NextN(runs[2], 1)[0])
+	assert.Equal(t, parseTime(t, "2025-01-06 11:00:00"), s.Prev(runs[2]))
+}
+
+func TestSchedule_JitterEndDate(t *testing.T) {
+	endDate := parseTime(t, "2025-01-06 10:05:00")
+	s, err := New(1, Hour,
+		SetEndDate(&endDate),
+		SetJitter(30*time.Minute),
+		SetJitterSource(rand.NewSource(3)),
+	)
+	require.NoError(t, err)
+
+	// The last run is delayed no further than the end date
+	last := s.Next(parseTime(t, "2025-01-06 09:00:00"))
+	assert.False(t, last.Before(parseTime(t, "2025-01-06 10:00:00")))
+	assert.False(t, last.After(endDate))
+	assert.True(t, s.Next(last).IsZero())
+}
+
+func TestSchedule_JitterValidation(t *testing.T) {
+	_, err := New(1, Hour, SetJitter(-time.Second))
+	assert.ErrorIs(t, err, ErrInvalidJitter)
+
+	_, err = New(1, Hour, SetSplay("web-1", -time.Second))
+	assert.ErrorIs(t, err, ErrInvalidJitter)
+}
+
+func TestSchedule_JitterConfig(t *testing.T) {
+	original, err := New(1, Hour, SetJitter(30*time.Second), SetSplay("web-1", 5*time.Minute))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(original.Config())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"jitter":"30s","splay":"5m0s","splay_key":"web-1"`)
+
+	var config Config
+	require.NoError(t, json.Unmarshal(data, &config))
+	decoded, err := NewFromConfig(config)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.jitter, decoded.jitter)
+	assert.Equal(t, original.splay, decoded.splay)
+	assert.Equal(t, original.splayMax, decoded.splayMax)
+}
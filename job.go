@@ -0,0 +1,45 @@
+package robfigcronschedule
+
+import "time"
+
+// Job is a unit of work run by robfig/cron. It matches cron.Job, so cron.FuncJob
+// values can be wrapped and the results passed to cron.Schedule.
+type Job interface {
+	Run()
+}
+
+// WrapJob returns a Job that runs job only while the schedule is enabled, so
+// wakeups of a disabled or paused schedule never execute user code.
+//
+// Together with SetDisabledMode(DisabledSleep) and SetWakeFunc, a disabled
+// schedule neither wakes nor runs the job, and resumes when enabled through
+// Set:
+//
+//	schedule, _ := New(15, Minute, SetDisabledMode(DisabledSleep))
+//	job := schedule.WrapJob(cron.FuncJob(sync))
+//	id := c.Schedule(schedule, job)
+//	schedule.Set(SetWakeFunc(func() {
+//	    c.Remove(id)
+//	    id = c.Schedule(schedule, job)
+//	}))
+func (s *Schedule) WrapJob(job Job) Job {
+	return &scheduleJob{schedule: s, job: job}
+}
+
+// scheduleJob is the Job returned by WrapJob.
+type scheduleJob struct {
+	schedule *Schedule
+	job      Job
+}
+
+// Run runs the wrapped job unless the schedule is disabled now.
+func (j *scheduleJob) Run() {
+	j.schedule.mu.Lock()
+	disabled := j.schedule.isDisabledAt(time.Now())
+	j.schedule.mu.Unlock()
+
+	if disabled {
+		return
+	}
+	j.job.Run()
+}
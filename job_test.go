@@ -0,0 +1,98 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJob counts its runs.
+type countingJob struct {
+	runs int
+}
+
+func (j *countingJob) Run() {
+	j.runs++
+}
+
+func TestSchedule_WrapJob(t *testing.T) {
+	schedule, err := New(1, Minute)
+	require.NoError(t, err)
+
+	job := &countingJob{}
+	wrapped := schedule.WrapJob(job)
+
+	wrapped.Run()
+	assert.Equal(t, 1, job.runs)
+
+	// Skipped while disabled or paused
+	require.NoError(t, schedule.Set(Disable()))
+	wrapped.Run()
+	require.NoError(t, schedule.Set(PauseUntil(time.Now().Add(time.Hour))))
+	wrapped.Run()
+	assert.Equal(t, 1, job.runs)
+
+	// Runs again once the pause is over
+	require.NoError(t, schedule.Set(PauseUntil(time.Now().Add(-time.Second))))
+	wrapped.Run()
+	assert.Equal(t, 2, job.runs)
+}
+
+func TestSchedule_DisabledSleep(t *testing.T) {
+	wakes := 0
+	schedule, err := New(1, Hour,
+		SetDisabledMode(DisabledSleep),
+		SetWakeFunc(func() { wakes++ }),
+	)
+	require.NoError(t, err)
+
+	now := parseTime(t, "2024-03-11 10:30:00")
+	assert.Equal(t, parseTime(t, "2024-03-11 11:30:00"), schedule.Next(now))
+
+	// Enabling a schedule that never slept does not wake it
+	require.NoError(t, schedule.Set(Disable()))
+	require.NoError(t, schedule.Set(Enable()))
+	assert.Equal(t, 0, wakes)
+
+	// A disabled schedule sleeps until it is enabled
+	require.NoError(t, schedule.Set(Disable(), SetNextRun(nil)))
+	assert.True(t, schedule.Next(now).IsZero())
+	require.NoError(t, schedule.Set(SetInterval(2)))
+	assert.Equal(t, 0, wakes)
+	require.NoError(t, schedule.Set(Enable()))
+	assert.Equal(t, 1, wakes)
+	assert.Equal(t, parseTime(t, "2024-03-11 12:30:00"), schedule.Next(now))
+
+	// Woken once
+	require.NoError(t, schedule.Set(Enable()))
+	assert.Equal(t, 1, wakes)
+
+	// Switching back to rechecking wakes it as well
+	require.NoError(t, schedule.Set(Disable(), SetNextRun(nil)))
+	assert.True(t, schedule.Next(now).IsZero())
+	require.NoError(t, schedule.Set(SetDisabledMode(DisabledRecheck)))
+	assert.Equal(t, 2, wakes)
+	assert.Equal(t, parseTime(t, "2024-03-11 10:35:00"), schedule.Next(now))
+
+	assert.ErrorIs(t, schedule.Set(SetDisabledMode(DisabledMode(7))), ErrInvalidDisabledMode)
+}
+
+func TestSchedule_DisabledSleepPause(t *testing.T) {
+	var changes []bool
+	schedule, err := New(2, Hour,
+		SetDisabledMode(DisabledSleep),
+		SetStateChangeFunc(func(enabled bool) { changes = append(changes, enabled) }),
+		PauseUntil(parseTime(t, "2024-03-11 13:00:00")),
+	)
+	require.NoError(t, err)
+
+	// The first run after the pause is scheduled directly
+	next := schedule.Next(parseTime(t, "2024-03-11 10:00:00"))
+	assert.Equal(t, parseTime(t, "2024-03-11 15:00:00"), next)
+	assert.Empty(t, changes)
+
+	assert.Equal(t, parseTime(t, "2024-03-11 17:00:00"), schedule.Next(next))
+	assert.Equal(t, []bool{true}, changes)
+}
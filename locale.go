@@ -0,0 +1,271 @@
+package robfigcronschedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// English describes schedules in English. It implements every optional
+// locale interface and stands in for locales that do not.
+var English Locale = english{}
+
+// Indonesian describes schedules in Indonesian (Bahasa Indonesia). It
+// implements every optional locale interface.
+var Indonesian Locale = indonesian{}
+
+type english struct{}
+
+func (english) Every(interval int, unit IntervalTimeUnit) string {
+	// Configuration names join words with underscores, as in "business_day"
+	name := strings.ReplaceAll(unit.String(), "_", " ")
+	if interval == 1 {
+		return "Every " + name
+	}
+	return fmt.Sprintf("Every %d %ss", interval, name)
+}
+
+func (english) Weekday(day time.Weekday) string {
+	return day.String()
+}
+
+func (english) Date(t time.Time) string {
+	return t.Format("2 Jan 2006")
+}
+
+func (english) List(items []string) string {
+	return joinList(items, " and ")
+}
+
+func (english) Starting(date string) string {
+	return "starting " + date
+}
+
+func (english) Until(date string) string {
+	return "until " + date
+}
+
+func (english) RunLimit(maxRuns, remainingRuns int) string {
+	limit := fmt.Sprintf("at most %d runs", maxRuns)
+	if maxRuns == 1 {
+		limit = "at most 1 run"
+	}
+	if remainingRuns != maxRuns {
+		limit += fmt.Sprintf(" (%d left)", remainingRuns)
+	}
+	return limit
+}
+
+func (english) NthDay(n int, weekday string) string {
+	if weekday == "" {
+		weekday = "day"
+	}
+	return "on the " + englishOrdinal(n) + " " + weekday + " of the month"
+}
+
+func (english) MonthEnd(policy MonthEndPolicy) string {
+	if policy == MonthEndSkip {
+		return "skipping months without the day"
+	}
+	return "on the last day of shorter months"
+}
+
+func (english) DSTGap(DSTGapPolicy) string {
+	return "skipping times lost to DST"
+}
+
+func (english) DSTOverlap(DSTOverlapPolicy) string {
+	return "twice in a repeated DST hour"
+}
+
+func (english) Aligned(anchor, offset string) string {
+	if anchor == "" {
+		anchor = "the clock"
+	}
+	if offset != "" {
+		return "aligned to " + anchor + " plus " + offset
+	}
+	return "aligned to " + anchor
+}
+
+func (english) Jitter(splay, jitter string) string {
+	switch {
+	case splay == "":
+		return "with up to " + jitter + " of random delay"
+	case jitter == "":
+		return "spread over " + splay
+	}
+	return "spread over " + splay + " with up to " + jitter + " of random delay"
+}
+
+func (english) Calendar() string {
+	return "except blocked dates"
+}
+
+func (english) Imprecise() string {
+	return "rounded up to the interval"
+}
+
+func (english) Disabled(description string) string {
+	return "Disabled: " + description
+}
+
+func (english) Paused(description, until string) string {
+	return "Paused until " + until + ": " + description
+}
+
+type indonesian struct{}
+
+// indonesianUnits names the interval units in Indonesian.
+var indonesianUnits = map[IntervalTimeUnit]string{
+	Second: "detik",
+	Minute: "menit",
+	Hour:   "jam",
+	Day:    "hari",
+	Week:   "minggu",
+	Month:  "bulan",
+	Year:   "tahun",
+
+	BusinessDay: "hari kerja",
+}
+
+// indonesianWeekdays names the days of the week in Indonesian, Sunday first.
+var indonesianWeekdays = [...]string{
+	"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu",
+}
+
+// indonesianMonths abbreviates the months in Indonesian, January first.
+var indonesianMonths = [...]string{
+	"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des",
+}
+
+func (indonesian) Every(interval int, unit IntervalTimeUnit) string {
+	name, ok := indonesianUnits[unit]
+	if !ok {
+		name = unit.String()
+	}
+	if interval == 1 {
+		return "Setiap " + name
+	}
+	return fmt.Sprintf("Setiap %d %s", interval, name)
+}
+
+func (indonesian) Weekday(day time.Weekday) string {
+	return indonesianWeekdays[day]
+}
+
+func (indonesian) Date(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
+
+func (indonesian) List(items []string) string {
+	return joinList(items, " dan ")
+}
+
+func (indonesian) Starting(date string) string {
+	return "mulai " + date
+}
+
+func (indonesian) Until(date string) string {
+	return "sampai " + date
+}
+
+func (indonesian) RunLimit(maxRuns, remainingRuns int) string {
+	limit := fmt.Sprintf("maksimal %d kali", maxRuns)
+	if remainingRuns != maxRuns {
+		limit += fmt.Sprintf(" (sisa %d)", remainingRuns)
+	}
+	return limit
+}
+
+func (indonesian) NthDay(n int, weekday string) string {
+	if weekday == "" {
+		weekday = "hari"
+	}
+	switch {
+	case n == -1:
+		return "pada " + weekday + " terakhir setiap bulan"
+	case n < 0:
+		return fmt.Sprintf("pada %s ke-%d dari akhir setiap bulan", weekday, -n)
+	default:
+		return fmt.Sprintf("pada %s ke-%d setiap bulan", weekday, n)
+	}
+}
+
+func (indonesian) MonthEnd(policy MonthEndPolicy) string {
+	if policy == MonthEndSkip {
+		return "melewati bulan tanpa tanggal tersebut"
+	}
+	return "pada hari terakhir bulan yang lebih pendek"
+}
+
+func (indonesian) DSTGap(DSTGapPolicy) string {
+	return "melewati waktu yang hilang karena DST"
+}
+
+func (indonesian) DSTOverlap(DSTOverlapPolicy) string {
+	return "dua kali pada jam DST yang berulang"
+}
+
+func (indonesian) Aligned(anchor, offset string) string {
+	if anchor == "" {
+		anchor = "jam"
+	}
+	if offset != "" {
+		return "selaras dengan " + anchor + " ditambah " + offset
+	}
+	return "selaras dengan " + anchor
+}
+
+func (indonesian) Jitter(splay, jitter string) string {
+	switch {
+	case splay == "":
+		return "dengan jeda acak hingga " + jitter
+	case jitter == "":
+		return "disebar dalam " + splay
+	}
+	return "disebar dalam " + splay + " dengan jeda acak hingga " + jitter
+}
+
+func (indonesian) Calendar() string {
+	return "kecuali tanggal yang diblokir"
+}
+
+func (indonesian) Imprecise() string {
+	return "dibulatkan ke atas sesuai interval"
+}
+
+func (indonesian) Disabled(description string) string {
+	return "Nonaktif: " + description
+}
+
+func (indonesian) Paused(description, until string) string {
+	return "Dijeda sampai " + until + ": " + description
+}
+
+// englishOrdinal formats an nth day such as "2nd", "last" or "3rd last".
+func englishOrdinal(n int) string {
+	switch {
+	case n == -1:
+		return "last"
+	case n < 0:
+		return englishOrdinal(-n) + " last"
+	case n%10 == 1 && n%100 != 11:
+		return fmt.Sprintf("%dst", n)
+	case n%10 == 2 && n%100 != 12:
+		return fmt.Sprintf("%dnd", n)
+	case n%10 == 3 && n%100 != 13:
+		return fmt.Sprintf("%drd", n)
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
+
+// joinList joins items with commas and the final conjunction, such as
+// "a, b and c".
+func joinList(items []string, conjunction string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + conjunction + items[len(items)-1]
+}
@@ -0,0 +1,159 @@
+package robfigcronschedule
+
+import "time"
+
+// maxMonthEndSkips bounds how many steps MonthEndSkip takes looking for a month
+// that has the anchor day. February 29 needs at most eight yearly steps.
+const maxMonthEndSkips = 48
+
+// addMonths adds months to t following the month end policy. With
+// MonthEndRollover it behaves like time.AddDate. Otherwise runs return to the
+// anchor day, and MonthEndSkip returns the zero time if no month within
+// maxMonthEndSkips steps has it.
+func (s *Schedule) addMonths(t time.Time, months int) time.Time {
+	if s.monthEnd == MonthEndRollover {
+		return addDate(t, 0, months, 0)
+	}
+
+	day := t.Day()
+	switch {
+	case s.startDate != nil:
+		day = s.startDate.In(t.Location()).Day()
+	case s.monthDay != 0:
+		day = s.monthDay
+	}
+
+	year, month, _ := t.Date()
+	for i := 1; i <= maxMonthEndSkips; i++ {
+		target := month + time.Month(months*i)
+		last := daysIn(year, target)
+		if day <= last || s.monthEnd == MonthEndClamp {
+			return firstAt(year, target, min(day, last),
+				t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+		}
+	}
+	return time.Time{}
+}
+
+// anchorMonthDay makes t's day of month the anchor day of Month and Year steps
+// if there is no start date and no earlier run set one.
+func (s *Schedule) anchorMonthDay(t time.Time) {
+	if s.monthDay == 0 && s.startDate == nil {
+		s.monthDay = t.Day()
+	}
+}
+
+// daysIn returns the number of days in a month, which is normalized like
+// time.Date.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nextNthDay returns the first run after t on the nth allowed day of every
+// interval-th month, not before the start date. It replaces steps 4-7 of Next
+// and returns the zero time if no month within maxMonthEndSkips steps has
+// such a day.
+func (s *Schedule) nextNthDay(t time.Time) time.Time {
+	s.trace.step(7, RuleMonthDay, "%s allowed day of every %s", englishOrdinal(s.nthDay),
+		formatTraceInterval(s.interval, s.intervalTimeUnit))
+
+	// Runs must be after floor
+	floor, from := t, t
+	if s.startDate != nil {
+		from = s.startDate.In(t.Location())
+		if limit := from.Add(-time.Nanosecond); limit.After(floor) {
+			floor = limit
+		}
+	}
+
+	year, month, _ := from.Date()
+	if elapsed := monthsBetween(from, floor); elapsed > 0 {
+		month += time.Month(elapsed / s.interval * s.interval)
+	}
+
+	for i := 0; i < maxMonthEndSkips; i++ {
+		run, ok := s.runOnNthDay(year, month+time.Month(i*s.interval), t.Location())
+		if !ok || !run.After(floor) {
+			continue
+		}
+		s.trace.propose(run, RuleMonthDay, "")
+		return run
+	}
+	return time.Time{}
+}
+
+// prevNthDay is the counterpart of nextNthDay for Prev. It returns the last run
+// before t, or the zero time if there is none.
+func (s *Schedule) prevNthDay(t time.Time) time.Time {
+	from := t
+	if s.startDate != nil {
+		from = s.startDate.In(t.Location())
+	}
+
+	year, month, _ := from.Date()
+	elapsed := monthsBetween(from, t)
+	if elapsed < 0 {
+		return time.Time{}
+	}
+	month += time.Month(elapsed / s.interval * s.interval)
+
+	for i := 0; i < maxMonthEndSkips; i++ {
+		// Months before the start date have no runs
+		if s.startDate != nil && i > elapsed/s.interval {
+			break
+		}
+
+		run, ok := s.runOnNthDay(year, month-time.Month(i*s.interval), t.Location())
+		if ok && run.Before(t) && (s.startDate == nil || !run.Before(*s.startDate)) {
+			return run
+		}
+	}
+	return time.Time{}
+}
+
+// runOnNthDay returns the run on the nth allowed day of a month, which is
+// normalized like time.Date: the first time window opening that day, or
+// midnight. ok is false if the month has no such day or no window opens on it.
+func (s *Schedule) runOnNthDay(year int, month time.Month, loc *time.Location) (time.Time, bool) {
+	last := daysIn(year, month)
+	count := 0
+	for i := 1; i <= last; i++ {
+		date := i
+		if s.nthDay < 0 {
+			date = last + 1 - i
+		}
+		day := firstAt(year, month, date, 0, 0, 0, 0, loc)
+		if !s.isDayAllowed(day) {
+			continue
+		}
+		if count++; count < abs(s.nthDay) {
+			continue
+		}
+
+		if !s.hasWindows() {
+			return day, true
+		}
+		if spans := s.windowsOn(day); len(spans) > 0 {
+			return spans[0].open, true
+		}
+		s.trace.reject(day, RuleMonthDay, RuleTimeWindow, "no time window")
+		return time.Time{}, false
+	}
+
+	s.trace.reject(firstAt(year, month, 1, 0, 0, 0, 0, loc), RuleMonthDay, RuleMonthDay,
+		"fewer than %d allowed days", abs(s.nthDay))
+	return time.Time{}, false
+}
+
+// monthsBetween returns the number of calendar months from a's month to b's.
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,247 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_MonthEndPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		interval  int
+		unit      IntervalTimeUnit
+		policy    MonthEndPolicy
+		startDate string
+		expected  []string
+	}{
+		{
+			name:      "rollover",
+			interval:  1,
+			unit:      Month,
+			policy:    MonthEndRollover,
+			startDate: "2025-01-31 00:00:00",
+			expected:  []string{"2025-01-31 00:00:00", "2025-03-03 00:00:00", "2025-04-03 00:00:00"},
+		},
+		{
+			name:      "clamp returns to the anchor day",
+			interval:  1,
+			unit:      Month,
+			policy:    MonthEndClamp,
+			startDate: "2025-01-31 00:00:00",
+			expected: []string{
+				"2025-01-31 00:00:00", "2025-02-28 00:00:00", "2025-03-31 00:00:00",
+				"2025-04-30 00:00:00", "2025-05-31 00:00:00",
+			},
+		},
+		{
+			name:      "skip",
+			interval:  1,
+			unit:      Month,
+			policy:    MonthEndSkip,
+			startDate: "2025-01-31 00:00:00",
+			expected:  []string{"2025-01-31 00:00:00", "2025-03-31 00:00:00", "2025-05-31 00:00:00"},
+		},
+		{
+			name:      "clamp every 3 months",
+			interval:  3,
+			unit:      Month,
+			policy:    MonthEndClamp,
+			startDate: "2024-08-31 00:00:00",
+			expected: []string{
+				"2024-08-31 00:00:00", "2024-11-30 00:00:00", "2025-02-28 00:00:00",
+				"2025-05-31 00:00:00",
+			},
+		},
+		{
+			name:      "leap day rollover",
+			interval:  1,
+			unit:      Year,
+			policy:    MonthEndRollover,
+			startDate: "2024-02-29 00:00:00",
+			expected:  []string{"2024-02-29 00:00:00", "2025-03-01 00:00:00", "2026-03-01 00:00:00"},
+		},
+		{
+			name:      "leap day clamp",
+			interval:  1,
+			unit:      Year,
+			policy:    MonthEndClamp,
+			startDate: "2024-02-29 00:00:00",
+			expected: []string{
+				"2024-02-29 00:00:00", "2025-02-28 00:00:00", "2026-02-28 00:00:00",
+				"2027-02-28 00:00:00", "2028-02-29 00:00:00",
+			},
+		},
+		{
+			name:      "leap day skip",
+			interval:  1,
+			unit:      Year,
+			policy:    MonthEndSkip,
+			startDate: "2096-02-29 00:00:00",
+			expected:  []string{"2096-02-29 00:00:00", "2104-02-29 00:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			startDate := parseTime(t, tt.startDate)
+			schedule, err := New(tt.interval, tt.unit,
+				SetStartDate(&startDate),
+				SetMonthEndPolicy(tt.policy),
+			)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(startDate.Add(-time.Hour), len(tt.expected))
+			expected := make([]time.Time, len(tt.expected))
+			for i, run := range tt.expected {
+				expected[i] = parseTime(t, run)
+			}
+			assert.Equal(t, expected, runs)
+
+			// Prev follows the same runs
+			last := len(runs) - 1
+			assert.Equal(t, runs[last-1], schedule.Prev(runs[last]))
+		})
+	}
+}
+
+func TestSchedule_MonthEndPolicyWithoutStartDate(t *testing.T) {
+	schedule, err := New(1, Month, SetMonthEndPolicy(MonthEndClamp))
+	require.NoError(t, err)
+
+	// Without a start date the day Next first steps from is the anchor
+	from := parseTime(t, "2025-01-31 00:00:00")
+	expected := []time.Time{
+		parseTime(t, "2025-02-28 00:00:00"),
+		parseTime(t, "2025-03-31 00:00:00"),
+		parseTime(t, "2025-04-30 00:00:00"),
+	}
+	assert.Equal(t, expected, schedule.NextN(from, 3))
+	assert.Equal(t, expected, runsLikeCron(schedule, from, 3))
+
+	// Setting the policy again takes the anchor from the next time
+	require.NoError(t, schedule.Set(SetMonthEndPolicy(MonthEndClamp)))
+	schedule.nextRun = time.Time{}
+	assert.Equal(t, parseTime(t, "2025-05-30 00:00:00"),
+		schedule.Next(parseTime(t, "2025-04-30 00:00:00")))
+}
+
+func TestSchedule_MonthEndPolicyValidation(t *testing.T) {
+	_, err := New(1, Month, SetMonthEndPolicy(MonthEndPolicy(3)))
+	assert.ErrorIs(t, err, ErrInvalidMonthEndPolicy)
+}
+
+func TestSchedule_NthDayOfMonth(t *testing.T) {
+	weekdays := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+	}
+	startDate := parseTime(t, "2025-01-15 00:00:00")
+
+	tests := []struct {
+		name     string
+		interval int
+		opts     []ScheduleOption
+		from     string
+		expected []string
+	}{
+		{
+			name:     "second Tuesday",
+			interval: 1,
+			opts:     []ScheduleOption{SetAllowedWeekdays(time.Tuesday), SetNthDayOfMonth(2)},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-01-14 00:00:00", "2025-02-11 00:00:00", "2025-03-11 00:00:00"},
+		},
+		{
+			name:     "last Friday",
+			interval: 1,
+			opts:     []ScheduleOption{SetAllowedWeekdays(time.Friday), SetNthDayOfMonth(-1)},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-01-31 00:00:00", "2025-02-28 00:00:00", "2025-03-28 00:00:00"},
+		},
+		{
+			name:     "last day",
+			interval: 1,
+			opts:     []ScheduleOption{SetNthDayOfMonth(-1)},
+			from:     "2024-01-31 00:00:00",
+			expected: []string{"2024-02-29 00:00:00", "2024-03-31 00:00:00", "2024-04-30 00:00:00"},
+		},
+		{
+			name:     "third day from the end",
+			interval: 1,
+			opts:     []ScheduleOption{SetNthDayOfMonth(-3)},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-01-29 00:00:00", "2025-02-26 00:00:00", "2025-03-29 00:00:00"},
+		},
+		{
+			name:     "fifth Monday skips months without one",
+			interval: 1,
+			opts:     []ScheduleOption{SetAllowedWeekdays(time.Monday), SetNthDayOfMonth(5)},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-03-31 00:00:00", "2025-06-30 00:00:00", "2025-09-29 00:00:00"},
+		},
+		{
+			name:     "last business day with holidays",
+			interval: 1,
+			opts: []ScheduleOption{
+				SetAllowedWeekdays(weekdays...),
+				SetCalendar(NewDateSet(parseTime(t, "2025-12-31 00:00:00"))),
+				SetNthDayOfMonth(-1),
+			},
+			from:     "2025-10-15 00:00:00",
+			expected: []string{"2025-10-31 00:00:00", "2025-11-28 00:00:00", "2025-12-30 00:00:00"},
+		},
+		{
+			name:     "quarterly in a time window from the start date",
+			interval: 3,
+			opts: []ScheduleOption{
+				SetAllowedWeekdays(time.Tuesday),
+				SetNthDayOfMonth(2),
+				SetTimeWindows(TimeWindow{
+					Start: time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC),
+					End:   time.Date(2000, 1, 1, 17, 0, 0, 0, time.UTC),
+				}),
+				SetStartDate(&startDate),
+			},
+			from:     "2025-01-01 00:00:00",
+			expected: []string{"2025-04-08 09:00:00", "2025-07-08 09:00:00", "2025-10-14 09:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(tt.interval, Month, tt.opts...)
+			require.NoError(t, err)
+
+			runs := schedule.NextN(parseTime(t, tt.from), len(tt.expected))
+			expected := make([]time.Time, len(tt.expected))
+			for i, run := range tt.expected {
+				expected[i] = parseTime(t, run)
+			}
+			assert.Equal(t, expected, runs)
+
+			// Prev walks the same runs backwards
+			for i := 1; i < len(runs); i++ {
+				assert.Equal(t, runs[i-1], schedule.Prev(runs[i]))
+			}
+			assert.Equal(t, runs[len(runs)-1], schedule.Prev(runs[len(runs)-1].Add(time.Hour)))
+		})
+	}
+}
+
+func TestSchedule_NthDayOfMonthValidation(t *testing.T) {
+	_, err := New(1, Day, SetNthDayOfMonth(1))
+	assert.ErrorIs(t, err, ErrInvalidNthDay)
+
+	_, err = New(1, Month, SetNthDayOfMonth(32))
+	assert.ErrorIs(t, err, ErrInvalidNthDay)
+
+	// Weekdays with a Month interval still need an nth day
+	_, err = New(1, Month, SetAllowedWeekdays(time.Tuesday))
+	assert.ErrorIs(t, err, ErrMultiIntervalWithWeekdayWindow)
+
+	schedule, err := New(1, Month, SetAllowedWeekdays(time.Tuesday), SetNthDayOfMonth(2))
+	require.NoError(t, err)
+	assert.ErrorIs(t, schedule.Set(SetNthDayOfMonth(0)), ErrMultiIntervalWithWeekdayWindow)
+}
@@ -1,6 +1,9 @@
 package robfigcronschedule
 
-import "time"
+import (
+	"math/rand"
+	"time"
+)
 
 type ScheduleOption func(*Schedule)
 
@@ -26,6 +29,8 @@ func SetStartTime(t *time.Time) ScheduleOption {
 // SetEndTime sets the daily end time for the schedule.
 // Must be used with SetStartTime to be meaningful.
 // The schedule will not run after this time each day.
+// If the end time is earlier than the start time, the window runs overnight
+// and closes on the following day.
 // Pass nil to reset/remove the end time constraint.
 //
 // Examples:
@@ -34,6 +39,10 @@ func SetStartTime(t *time.Time) ScheduleOption {
 //	t := time.Date(2000, 1, 1, 17, 0, 0, 0, time.UTC)
 //	SetEndTime(&t)
 //
+//	// Overnight window from 10 PM to 4 AM (with SetStartTime at 10 PM):
+//	t := time.Date(2000, 1, 1, 4, 0, 0, 0, time.UTC)
+//	SetEndTime(&t)
+//
 //	// Reset end time (defaults to 23:59:59):
 //	SetEndTime(nil)
 func SetEndTime(t *time.Time) ScheduleOption {
@@ -42,6 +51,79 @@ func SetEndTime(t *time.Time) ScheduleOption {
 	}
 }
 
+// SetTimeWindows sets several daily time windows for the schedule.
+// The next run is the earliest eligible slot across all windows; when stepping
+// overflows one window, the schedule moves on to the next window that opens.
+// Windows must not overlap and cannot be combined with SetStartTime/SetEndTime.
+// Pass no arguments to reset/remove the time windows.
+//
+// Examples:
+//
+//	// Run during morning and afternoon shifts:
+//	SetTimeWindows(
+//	    TimeWindow{
+//	        Start: time.Date(2000, 1, 1, 8, 0, 0, 0, time.UTC),
+//	        End:   time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC),
+//	    },
+//	    TimeWindow{
+//	        Start: time.Date(2000, 1, 1, 13, 0, 0, 0, time.UTC),
+//	        End:   time.Date(2000, 1, 1, 17, 0, 0, 0, time.UTC),
+//	    },
+//	)
+//
+//	// Reset time windows:
+//	SetTimeWindows() // empty arguments
+func SetTimeWindows(windows ...TimeWindow) ScheduleOption {
+	return func(s *Schedule) {
+		if len(windows) < 1 {
+			s.timeWindows = nil
+			return
+		}
+
+		s.timeWindows = append([]TimeWindow(nil), windows...)
+	}
+}
+
+// SetWeeklyTimeWindows sets time windows per weekday, so each day of the week
+// can have its own windows. Days missing from the table are skipped entirely.
+// Windows of one day must not overlap each other, nor the windows of the next
+// day when they run overnight. Cannot be combined with SetStartTime/SetEndTime
+// or SetTimeWindows.
+// Pass nil to reset/remove the weekly time windows.
+//
+// Examples:
+//
+//	// Mon-Fri 09:00-18:00 plus Sat 10:00-14:00:
+//	office := TimeWindow{
+//	    Start: time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC),
+//	    End:   time.Date(2000, 1, 1, 18, 0, 0, 0, time.UTC),
+//	}
+//	saturday := TimeWindow{
+//	    Start: time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC),
+//	    End:   time.Date(2000, 1, 1, 14, 0, 0, 0, time.UTC),
+//	}
+//	SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+//	    time.Monday:    {office},
+//	    time.Tuesday:   {office},
+//	    time.Wednesday: {office},
+//	    time.Thursday:  {office},
+//	    time.Friday:    {office},
+//	    time.Saturday:  {saturday},
+//	})
+//
+//	// Reset weekly time windows:
+//	SetWeeklyTimeWindows(nil)
+func SetWeeklyTimeWindows(table map[time.Weekday][]TimeWindow) ScheduleOption {
+	return func(s *Schedule) {
+		if table == nil {
+			s.weeklyWindows = nil
+			return
+		}
+
+		s.weeklyWindows = copyWeeklyWindows(table)
+	}
+}
+
 // SetStartDate sets when the schedule should begin executing.
 // The schedule will not run before this date.
 // Pass nil to reset/remove the start date constraint.
@@ -61,6 +143,28 @@ func SetEndTime(t *time.Time) ScheduleOption {
 func SetStartDate(t *time.Time) ScheduleOption {
 	return func(s *Schedule) {
 		s.startDate = t
+		s.monthDay = 0
+	}
+}
+
+// SetEndDate sets when the schedule should stop executing.
+// Runs after this instant are never produced: once the end date has passed,
+// Next() returns the zero time, which robfig/cron treats as "never run again",
+// and the after hook receives that zero time.
+// Must not be before the start date.
+// Pass nil to reset/remove the end date constraint.
+//
+// Examples:
+//
+//	// Stop at the end of the peak season:
+//	endDate := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+//	SetEndDate(&endDate)
+//
+//	// Reset end date (schedule never expires):
+//	SetEndDate(nil)
+func SetEndDate(t *time.Time) ScheduleOption {
+	return func(s *Schedule) {
+		s.endDate = t
 	}
 }
 
@@ -83,8 +187,47 @@ func SetNextRun(t *time.Time) ScheduleOption {
 	}
 }
 
+// SetMaxRuns limits how many distinct run times the schedule produces.
+// Once the limit is reached, Next() returns the zero time, which robfig/cron
+// treats as "never run again". Setting the limit also resets the remaining
+// run count to n.
+// Pass 0 to remove the limit.
+//
+// Examples:
+//
+//	// Run 5 times every 2 hours, then stop:
+//	New(2, Hour, SetMaxRuns(5))
+//
+//	// Remove the limit:
+//	SetMaxRuns(0)
+func SetMaxRuns(n int) ScheduleOption {
+	return func(s *Schedule) {
+		s.maxRuns = n
+		s.remainingRuns = n
+		s.lastRun = time.Time{}
+	}
+}
+
+// SetRemainingRuns overrides how many runs are left under the limit set by
+// SetMaxRuns. Must be between 0 and the maximum runs.
+//
+// Examples:
+//
+//	// Start the drip campaign over:
+//	SetRemainingRuns(5)
+//
+//	// Stop after the current run:
+//	SetRemainingRuns(0)
+func SetRemainingRuns(n int) ScheduleOption {
+	return func(s *Schedule) {
+		s.remainingRuns = n
+	}
+}
+
 // SetAllowedWeekdays restricts the schedule to run only on specified weekdays.
-// If not set or if no weekdays are provided, the schedule can run on any day.
+// If not set or if no weekdays are provided, the schedule can run on any day,
+// or Monday to Friday with the BusinessDay unit. BusinessDay intervals count
+// only the allowed weekdays.
 //
 // When combined with multi-day intervals (Week, Month, Year), this may produce
 // unexpected results as the schedule will skip to the next allowed day,
@@ -115,6 +258,73 @@ func SetAllowedWeekdays(weekdays ...time.Weekday) ScheduleOption {
 	}
 }
 
+// SetLocation pins the schedule to loc. Time windows, start and end dates,
+// weekdays and the calendar are then evaluated in loc, whatever the location
+// of the time passed to Next, and results are returned in the caller's
+// location. Times of day and dates keep their own location and are converted
+// to loc, so give them in loc to use its wall clock.
+// Pass nil to evaluate in the location of the time passed to Next (default).
+//
+// Examples:
+//
+//	// Office hours in Jakarta, whether cron runs in UTC or local time:
+//	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+//	start := time.Date(2000, 1, 1, 9, 0, 0, 0, jakarta)
+//	New(30, Minute, SetLocation(jakarta), SetStartTime(&start))
+//
+//	// Follow the caller's location again:
+//	SetLocation(nil)
+func SetLocation(loc *time.Location) ScheduleOption {
+	return func(s *Schedule) {
+		s.location = loc
+	}
+}
+
+// SetDSTPolicy sets how time windows whose opening time a daylight saving
+// time transition skips or repeats are handled. Steps of Second, Minute and
+// Hour intervals count elapsed time and are not affected. Steps of Day, Week,
+// Month and Year intervals keep the wall-clock time, shifted forward out of a
+// gap.
+//
+// gap is DSTShiftForward (default) or DSTSkip, overlap is DSTRunOnce
+// (default) or DSTRunTwice. A window end in a gap is always shifted forward,
+// and a repeated one is the first occurrence, or the second with DSTRunTwice.
+//
+// Examples:
+//
+//	// A nightly 02:30 job that must not run at 03:30 instead:
+//	SetDSTPolicy(DSTSkip, DSTRunOnce)
+//
+//	// Restore the defaults:
+//	SetDSTPolicy(DSTShiftForward, DSTRunOnce)
+func SetDSTPolicy(gap DSTGapPolicy, overlap DSTOverlapPolicy) ScheduleOption {
+	return func(s *Schedule) {
+		s.dstGap = gap
+		s.dstOverlap = overlap
+	}
+}
+
+// SetCalendar sets a Calendar of blocked dates, such as public holidays or
+// change freezes. Blocked dates are skipped like disallowed weekdays.
+// Pass nil to reset/remove the calendar.
+//
+// Examples:
+//
+//	// Skip public holidays:
+//	holidays, _ := LoadDateSetFile("holidays.txt")
+//	SetCalendar(holidays)
+//
+//	// Skip specific dates:
+//	SetCalendar(NewDateSet(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)))
+//
+//	// Reset calendar:
+//	SetCalendar(nil)
+func SetCalendar(c Calendar) ScheduleOption {
+	return func(s *Schedule) {
+		s.calendar = c
+	}
+}
+
 // SetInterval override how often the schedule should run.
 // Must be >= 1. Use with SetIntervalTimeUnit to specify the unit.
 //
@@ -129,7 +339,7 @@ func SetInterval(i int) ScheduleOption {
 }
 
 // SetIntervalTimeUnit override the time unit for intervals.
-// Use one of: Second, Minute, Hour, Day, Week, Month, Year
+// Use one of: Second, Minute, Hour, Day, Week, Month, Year, BusinessDay
 //
 // Examples:
 //
@@ -139,6 +349,175 @@ func SetInterval(i int) ScheduleOption {
 func SetIntervalTimeUnit(i IntervalTimeUnit) ScheduleOption {
 	return func(s *Schedule) {
 		s.intervalTimeUnit = i
+		s.monthDay = 0
+	}
+}
+
+// SetNthDayOfMonth restricts a Month interval to the nth allowed day of the
+// month: n from 1 to 31 counts from the start of the month and -1 to -31 from
+// its end, so -1 is the last day. Only days allowed by SetAllowedWeekdays and
+// the calendar are counted, and months with fewer such days are skipped.
+// Runs happen at the first time window opening that day, or at midnight.
+// Every interval-th month is counted from the start date's month, or from the
+// month of the time Next is given without a start date. Pass 0 to remove the
+// restriction.
+//
+// Examples:
+//
+//	// Second Tuesday of each month:
+//	New(1, Month, SetAllowedWeekdays(time.Tuesday), SetNthDayOfMonth(2))
+//
+//	// Last business day of each quarter, skipping holidays:
+//	New(3, Month,
+//	    SetAllowedWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday),
+//	    SetCalendar(holidays),
+//	    SetNthDayOfMonth(-1),
+//	)
+//
+//	// Third day from the end of each month:
+//	New(1, Month, SetNthDayOfMonth(-3))
+func SetNthDayOfMonth(n int) ScheduleOption {
+	return func(s *Schedule) {
+		s.nthDay = n
+	}
+}
+
+// SetMonthEndPolicy sets how Month and Year steps handle a day of the month
+// that the target month lacks. Runs keep the start date's day of month, or
+// without a start date the day of the first time Next computes a run from, so
+// after a clamped run on February 28 a schedule starting on January 31 returns
+// to March 31. Setting the policy, start date or time unit resets that day.
+//
+// Examples:
+//
+//	// Bill on the last day of short months:
+//	SetMonthEndPolicy(MonthEndClamp)
+//
+//	// Only run in months that have the day, like an RRULE:
+//	SetMonthEndPolicy(MonthEndSkip)
+//
+//	// Restore the default (time.AddDate):
+//	SetMonthEndPolicy(MonthEndRollover)
+func SetMonthEndPolicy(p MonthEndPolicy) ScheduleOption {
+	return func(s *Schedule) {
+		s.monthEnd = p
+		s.monthDay = 0
+	}
+}
+
+// AlignToClock aligns the runs of a Second, Minute or Hour interval to the
+// wall clock: every day, runs happen at midnight plus offset plus multiples of
+// the interval, such as :00, :15, :30 and :45 for a 15-minute interval. Time
+// windows and start dates then begin at the next aligned time instead of
+// their own start. The interval must be at most a day and offset must be
+// below it.
+//
+// Examples:
+//
+//	// Every 15 minutes on the quarter hour:
+//	New(15, Minute, AlignToClock(0))
+//
+//	// Every hour at :07:
+//	New(1, Hour, AlignToClock(7*time.Minute))
+func AlignToClock(offset time.Duration) ScheduleOption {
+	return func(s *Schedule) {
+		s.alignMode = AlignClock
+		s.alignAnchor = time.Time{}
+		s.alignOffset = offset
+	}
+}
+
+// AlignToAnchor aligns the runs of a Second, Minute or Hour interval to a
+// fixed epoch: runs happen at anchor plus offset plus any multiple of the
+// interval, before or after the anchor. Unlike AlignToClock the grid counts
+// elapsed time, so it is not reset at midnight. offset must be below the
+// interval.
+//
+// Examples:
+//
+//	// Every 90 minutes from Monday 09:00:
+//	New(90, Minute, AlignToAnchor(time.Date(2025, 1, 6, 9, 0, 0, 0, loc), 0))
+func AlignToAnchor(anchor time.Time, offset time.Duration) ScheduleOption {
+	return func(s *Schedule) {
+		s.alignMode = AlignAnchor
+		s.alignAnchor = anchor
+		s.alignOffset = offset
+	}
+}
+
+// DisableAlignment removes any alignment set by AlignToClock or AlignToAnchor
+// (default).
+//
+// Example:
+//
+//	DisableAlignment()
+func DisableAlignment() ScheduleOption {
+	return func(s *Schedule) {
+		s.alignMode = AlignNone
+		s.alignAnchor = time.Time{}
+		s.alignOffset = 0
+	}
+}
+
+// SetJitter delays every run returned by Next by a random duration from 0 up
+// to maxDelay, so schedules shared by many processes do not all run at the
+// same moment. Delays never move a run out of its time window or day, past the
+// next step of a Second, Minute or Hour interval, or past the end date: when
+// less room is left, the delay is scaled down to fit. Later runs are computed
+// from the undelayed run, so delays do not add up. NextN, Between and Prev
+// return undelayed runs. Pass 0 to remove the jitter.
+//
+// Examples:
+//
+//	// Spread hourly runs over up to 5 minutes:
+//	New(1, Hour, SetJitter(5*time.Minute))
+//
+//	// Remove the jitter:
+//	SetJitter(0)
+func SetJitter(maxDelay time.Duration) ScheduleOption {
+	return func(s *Schedule) {
+		s.jitter = maxDelay
+		s.jitterBase, s.jitterRun = time.Time{}, time.Time{}
+	}
+}
+
+// SetSplay delays every run returned by Next by a fixed duration below
+// maxDelay derived from key, such as the hostname, so each process runs at
+// its own offset that stays the same across restarts. It is bounded like
+// SetJitter, and with both the delays add up. Pass a maxDelay of 0 to remove
+// the splay.
+//
+// Examples:
+//
+//	// Each pod runs at its own minute within the first 10 of the hour:
+//	host, _ := os.Hostname()
+//	New(1, Hour, AlignToClock(0), SetSplay(host, 10*time.Minute))
+func SetSplay(key string, maxDelay time.Duration) ScheduleOption {
+	return func(s *Schedule) {
+		s.splayKey = key
+		s.splayMax = maxDelay
+		s.splay = splayOffset(key, maxDelay)
+		s.jitterBase, s.jitterRun = time.Time{}, time.Time{}
+	}
+}
+
+// SetJitterSource sets the random source of SetJitter, for example a seeded
+// source in tests. The source is guarded by its own lock, so the option may
+// be passed to several schedules, which then share the source.
+// Pass nil to restore the math/rand default.
+//
+// Example:
+//
+//	SetJitterSource(rand.NewSource(42))
+func SetJitterSource(src rand.Source) ScheduleOption {
+	if src != nil {
+		src = &lockedSource{src: src}
+	}
+	return func(s *Schedule) {
+		s.jitterRand = nil
+		if src != nil {
+			s.jitterRand = rand.New(src)
+		}
 	}
 }
 
@@ -181,7 +560,49 @@ func SetAfterNextFunc(f func(next *time.Time)) ScheduleOption {
 	}
 }
 
-// Enable activates the schedule (default state).
+// SetStateChangeFunc sets a function to call when the schedule is disabled or
+// resumes, either through Set or when a pause set by PauseUntil ends.
+// Useful for alerting or audit logs.
+// Pass nil to remove the hook.
+//
+// Examples:
+//
+//	// Log pauses and resumptions:
+//	SetStateChangeFunc(func(enabled bool) {
+//	    log.Printf("Schedule enabled: %v", enabled)
+//	})
+//
+//	// Remove the hook:
+//	SetStateChangeFunc(nil)
+func SetStateChangeFunc(f func(enabled bool)) ScheduleOption {
+	return func(s *Schedule) {
+		s.stateChange = f
+	}
+}
+
+// SetWakeFunc sets a function to call when Set gives a schedule sleeping in
+// DisabledSleep mode runs again, for example by enabling it. robfig/cron does
+// not ask a schedule again once Next returned the zero time, so the hook
+// should re-add the cron entry.
+// Pass nil to remove the hook.
+//
+// Examples:
+//
+//	// Re-add the entry when the schedule is enabled again:
+//	SetWakeFunc(func() {
+//	    c.Remove(id)
+//	    id = c.Schedule(schedule, job)
+//	})
+//
+//	// Remove the hook:
+//	SetWakeFunc(nil)
+func SetWakeFunc(f func()) ScheduleOption {
+	return func(s *Schedule) {
+		s.wake = f
+	}
+}
+
+// Enable activates the schedule (default state) and ends any pause.
 //
 // Example:
 //
@@ -189,12 +610,14 @@ func SetAfterNextFunc(f func(next *time.Time)) ScheduleOption {
 func Enable() ScheduleOption {
 	return func(s *Schedule) {
 		s.enabled = true
+		s.pausedUntil = nil
 	}
 }
 
-// Disable deactivates the schedule.
-// When disabled, Next() returns current time + 5 minutes, causing the cron job
-// to run every 5 minutes but without executing the actual scheduled logic.
+// Disable deactivates the schedule until it is enabled again.
+// When disabled, Next() returns current time + the recheck interval (5 minutes
+// by default), causing the cron job to run periodically but without executing
+// the actual scheduled logic.
 // This allows for periodic re-checking of the schedule state.
 //
 // Example:
@@ -203,6 +626,61 @@ func Enable() ScheduleOption {
 func Disable() ScheduleOption {
 	return func(s *Schedule) {
 		s.enabled = false
+		s.pausedUntil = nil
+	}
+}
+
+// PauseUntil disables the schedule until t. Next behaves as with Disable,
+// but rechecks no later than t, and from t on the schedule is enabled again
+// without another call to Set.
+//
+// Examples:
+//
+//	// Pause over the maintenance window:
+//	PauseUntil(time.Date(2025, 1, 6, 6, 0, 0, 0, time.UTC))
+//
+//	// Resume now:
+//	Enable()
+func PauseUntil(t time.Time) ScheduleOption {
+	return func(s *Schedule) {
+		s.enabled = false
+		s.pausedUntil = &t
+	}
+}
+
+// SetDisabledMode sets what Next returns while the schedule is disabled.
+// DisabledRecheck (default) wakes the cron job at the recheck interval.
+// DisabledSleep returns the zero time instead, so robfig/cron stops calling
+// the job until SetWakeFunc's hook re-adds it; a paused schedule sleeps until
+// the first run after its pause.
+//
+// Examples:
+//
+//	// Never wake the job while disabled:
+//	SetDisabledMode(DisabledSleep)
+//
+//	// Restore the default:
+//	SetDisabledMode(DisabledRecheck)
+func SetDisabledMode(m DisabledMode) ScheduleOption {
+	return func(s *Schedule) {
+		s.disabledMode = m
+	}
+}
+
+// SetDisabledRecheckInterval sets how far ahead Next schedules the next check
+// while the schedule is disabled or paused. Must not be negative.
+// Pass 0 to restore the default of 5 minutes.
+//
+// Examples:
+//
+//	// Pick up Set(Enable()) within 30 seconds:
+//	SetDisabledRecheckInterval(30 * time.Second)
+//
+//	// Restore the default:
+//	SetDisabledRecheckInterval(0)
+func SetDisabledRecheckInterval(d time.Duration) ScheduleOption {
+	return func(s *Schedule) {
+		s.disabledRecheck = d
 	}
 }
 
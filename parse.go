@@ -0,0 +1,825 @@
+package robfigcronschedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dslUnits maps the interval units of the text format to IntervalTimeUnit.
+// The first name of each unit is the canonical one.
+var dslUnits = []struct {
+	unit  IntervalTimeUnit
+	names []string
+}{
+	{Second, []string{"s", "sec", "second", "seconds"}},
+	{Minute, []string{"m", "min", "minute", "minutes"}},
+	{Hour, []string{"h", "hour", "hours"}},
+	{Day, []string{"d", "day", "days"}},
+	{Week, []string{"w", "week", "weeks"}},
+	{Month, []string{"mo", "month", "months"}},
+	{Year, []string{"y", "year", "years"}},
+	{BusinessDay, []string{"bd", "bday", "bdays"}},
+}
+
+// dslWeekdays lists weekdays in the order used by the text format.
+var dslWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+// ParseError reports a problem in a schedule string and where it occurred.
+type ParseError struct {
+	// Input is the string being parsed.
+	Input string
+	// Column is the 1-based byte position of the problem in Input.
+	Column int
+	// Msg describes the problem.
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid schedule at column %d: %s", e.Column, e.Msg)
+}
+
+// Unwrap makes errors.Is(err, ErrInvalidScheduleString) report true.
+func (e *ParseError) Unwrap() error {
+	return ErrInvalidScheduleString
+}
+
+// dslToken is a part of the input together with its byte offset.
+type dslToken struct {
+	text   string
+	offset int
+}
+
+// Parse creates a Schedule from its text form, as produced by String.
+// Clauses are separated by spaces and may appear in any order:
+//
+//	every 30m                          interval and unit (s, m, h, d, w, mo, y)
+//	between 09:00-17:00                daily time window, 24:00 is the end of day
+//	between 08:00-12:00,13:00-17:00    multiple daily time windows
+//	weekly mon-fri=09:00-18:00;sat=10:00-14:00
+//	                                   per-weekday time windows
+//	on mon-fri                         allowed weekdays, also "on mon,wed,fri"
+//	from 2025-01-06                    start date, also "from 2025-01-06T09:00"
+//	until 2025-06-30T18:00             end date
+//	limit 10                           maximum runs
+//	remaining 4                        remaining runs
+//	disabled                           disable the schedule
+//	paused-until 2025-01-06T06:00      disable the schedule until a date
+//	recheck 30s                        recheck interval while disabled
+//	sleep                              sleep instead of rechecking while disabled
+//	imprecise                          disable precision mode
+//	tz=Asia/Jakarta                    location of all dates and times (UTC)
+//	pinned                             evaluate in the tz location, see SetLocation
+//	nth -1                             nth allowed day of the month, see SetNthDayOfMonth
+//	month-end clamp                    month end policy: rollover, clamp or skip
+//	dst-skip                           skip times in a DST gap, see SetDSTPolicy
+//	dst-twice                          run twice at times a DST change repeats
+//	align clock+7m                     align runs to the clock, see AlignToClock
+//	align 2025-01-06T09:00             align runs to an anchor, see AlignToAnchor
+//	jitter 30s                         random delay of each run, see SetJitter
+//	splay 5m@web-1                     fixed delay derived from a key, see SetSplay,
+//	                                   quoted like "web 1" if it has spaces
+//
+// Only "every" is required. Options that have no text form, such as hooks
+// and calendars, can be passed as additional options. The result is
+// validated like New.
+//
+// Returns a *ParseError, which wraps ErrInvalidScheduleString, when the text
+// is malformed.
+//
+// Example:
+//
+//	schedule, err := Parse("every 30m between 09:00-17:00 on mon-fri tz=Asia/Jakarta")
+func Parse(text string, opts ...ScheduleOption) (*Schedule, error) {
+	p := &dslParser{input: text, tokens: tokenize(text, 0, unicode.IsSpace), loc: time.UTC}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return New(p.interval, p.unit, append(p.opts, opts...)...)
+}
+
+// dslParser holds the state of Parse.
+type dslParser struct {
+	input    string
+	tokens   []dslToken
+	pos      int
+	loc      *time.Location
+	seen     map[string]bool
+	interval int
+	unit     IntervalTimeUnit
+	gap      DSTGapPolicy
+	overlap  DSTOverlapPolicy
+	opts     []ScheduleOption
+}
+
+func (p *dslParser) parse() error {
+	// The location applies to every clause, so resolve it first
+	for _, tok := range p.tokens {
+		if strings.HasPrefix(strings.ToLower(tok.text), "tz=") {
+			name := tok.text[len("tz="):]
+			loc, err := time.LoadLocation(name)
+			if err != nil || name == "" {
+				return p.errorAt(tok.offset+len("tz="), "unknown time zone %q", name)
+			}
+			p.loc = loc
+		}
+	}
+
+	p.seen = make(map[string]bool)
+	for p.pos < len(p.tokens) {
+		tok := p.next()
+		keyword := strings.ToLower(tok.text)
+		if strings.HasPrefix(keyword, "tz=") {
+			keyword = "tz"
+		}
+		if p.seen[keyword] {
+			return p.errorAt(tok.offset, "duplicate %q clause", keyword)
+		}
+		p.seen[keyword] = true
+
+		var err error
+		switch keyword {
+		case "every":
+			err = p.parseEvery()
+		case "between":
+			err = p.parseBetween()
+		case "weekly":
+			err = p.parseWeekly()
+		case "on":
+			err = p.parseOn()
+		case "from", "until":
+			err = p.parseDate(keyword)
+		case "limit", "remaining":
+			err = p.parseRuns(keyword)
+		case "disabled":
+			p.opts = append(p.opts, Disable())
+		case "paused-until":
+			err = p.parseDate(keyword)
+		case "recheck":
+			err = p.parseRecheck()
+		case "nth":
+			err = p.parseNth()
+		case "month-end":
+			err = p.parseMonthEnd()
+		case "align":
+			err = p.parseAlign()
+		case "jitter":
+			err = p.parseJitter()
+		case "splay":
+			err = p.parseSplay()
+		case "sleep":
+			p.opts = append(p.opts, SetDisabledMode(DisabledSleep))
+		case "pinned":
+			p.opts = append(p.opts, SetLocation(p.loc))
+		case "dst-skip":
+			p.gap = DSTSkip
+		case "dst-twice":
+			p.overlap = DSTRunTwice
+		case "imprecise":
+			p.opts = append(p.opts, DisablePrecision())
+		case "tz":
+		default:
+			err = p.errorAt(tok.offset, "unexpected %q", tok.text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !p.seen["every"] {
+		return p.errorAt(0, `missing "every" clause`)
+	}
+	p.opts = append(p.opts, SetDSTPolicy(p.gap, p.overlap))
+	return nil
+}
+
+// next returns the next token and advances.
+func (p *dslParser) next() dslToken {
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok
+}
+
+// value returns the token following a keyword, or an error pointing at the
+// end of the input.
+func (p *dslParser) value(keyword string) (dslToken, error) {
+	if p.pos >= len(p.tokens) {
+		return dslToken{}, p.errorAt(len(p.input), "missing value after %q", keyword)
+	}
+	return p.next(), nil
+}
+
+func (p *dslParser) parseEvery() error {
+	tok, err := p.value("every")
+	if err != nil {
+		return err
+	}
+
+	number, unitTok := tok.text, dslToken{}
+	digits := strings.IndexFunc(tok.text, func(r rune) bool { return !unicode.IsDigit(r) })
+	switch {
+	case digits == 0:
+		return p.errorAt(tok.offset, "invalid interval %q", tok.text)
+	case digits < 0:
+		// "every 2 hours": the unit is the next token
+		if unitTok, err = p.value(tok.text); err != nil {
+			return err
+		}
+	default:
+		number = tok.text[:digits]
+		unitTok = dslToken{text: tok.text[digits:], offset: tok.offset + digits}
+	}
+
+	if p.interval, err = strconv.Atoi(number); err != nil {
+		return p.errorAt(tok.offset, "invalid interval %q", number)
+	}
+
+	for _, u := range dslUnits {
+		for _, name := range u.names {
+			if strings.EqualFold(name, unitTok.text) {
+				p.unit = u.unit
+				return nil
+			}
+		}
+	}
+	return p.errorAt(unitTok.offset, "unknown interval unit %q", unitTok.text)
+}
+
+func (p *dslParser) parseBetween() error {
+	tok, err := p.value("between")
+	if err != nil {
+		return err
+	}
+
+	windows, err := p.parseWindows(tok)
+	if err != nil {
+		return err
+	}
+	if len(windows) > 1 {
+		p.opts = append(p.opts, SetTimeWindows(windows...))
+		return nil
+	}
+
+	start, end := windows[0].Start, windows[0].End
+	p.opts = append(p.opts, SetStartTime(&start))
+	if !end.IsZero() {
+		p.opts = append(p.opts, SetEndTime(&end))
+	}
+	return nil
+}
+
+func (p *dslParser) parseWeekly() error {
+	tok, err := p.value("weekly")
+	if err != nil {
+		return err
+	}
+
+	table := make(map[time.Weekday][]TimeWindow)
+	for _, entry := range tokenize(tok.text, tok.offset, isRune(';')) {
+		days, windows, ok := strings.Cut(entry.text, "=")
+		if !ok {
+			return p.errorAt(entry.offset, "expected DAYS=WINDOWS, got %q", entry.text)
+		}
+
+		weekdays, err := p.parseWeekdays(dslToken{text: days, offset: entry.offset})
+		if err != nil {
+			return err
+		}
+		parsed, err := p.parseWindows(dslToken{text: windows, offset: entry.offset + len(days) + 1})
+		if err != nil {
+			return err
+		}
+		for _, day := range weekdays {
+			if _, ok := table[day]; ok {
+				return p.errorAt(entry.offset, "duplicate weekday %q", formatDSLWeekday(day))
+			}
+			table[day] = parsed
+		}
+	}
+
+	p.opts = append(p.opts, SetWeeklyTimeWindows(table))
+	return nil
+}
+
+func (p *dslParser) parseOn() error {
+	tok, err := p.value("on")
+	if err != nil {
+		return err
+	}
+
+	weekdays, err := p.parseWeekdays(tok)
+	if err != nil {
+		return err
+	}
+	p.opts = append(p.opts, SetAllowedWeekdays(weekdays...))
+	return nil
+}
+
+func (p *dslParser) parseDate(keyword string) error {
+	tok, err := p.value(keyword)
+	if err != nil {
+		return err
+	}
+
+	t, ok := p.parseTime(tok.text)
+	if !ok {
+		return p.errorAt(tok.offset, "invalid date %q", tok.text)
+	}
+
+	switch keyword {
+	case "from":
+		p.opts = append(p.opts, SetStartDate(&t))
+	case "until":
+		p.opts = append(p.opts, SetEndDate(&t))
+	default:
+		p.opts = append(p.opts, PauseUntil(t))
+	}
+	return nil
+}
+
+// parseTime parses a date, optionally with a time of day, in the parser's
+// location.
+func (p *dslParser) parseTime(text string) (time.Time, bool) {
+	for _, layout := range []string{dateLayout, "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, text, p.loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (p *dslParser) parseRecheck() error {
+	tok, err := p.value("recheck")
+	if err != nil {
+		return err
+	}
+
+	d, err := time.ParseDuration(tok.text)
+	if err != nil || d < 0 {
+		return p.errorAt(tok.offset, "invalid duration %q", tok.text)
+	}
+	p.opts = append(p.opts, SetDisabledRecheckInterval(d))
+	return nil
+}
+
+func (p *dslParser) parseNth() error {
+	tok, err := p.value("nth")
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(tok.text)
+	if err != nil || n == 0 || n < -31 || n > 31 {
+		return p.errorAt(tok.offset, "invalid nth day %q", tok.text)
+	}
+	p.opts = append(p.opts, SetNthDayOfMonth(n))
+	return nil
+}
+
+func (p *dslParser) parseMonthEnd() error {
+	tok, err := p.value("month-end")
+	if err != nil {
+		return err
+	}
+
+	var policy MonthEndPolicy
+	if policy.UnmarshalText([]byte(tok.text)) != nil {
+		return p.errorAt(tok.offset, "unknown month end policy %q", tok.text)
+	}
+	p.opts = append(p.opts, SetMonthEndPolicy(policy))
+	return nil
+}
+
+func (p *dslParser) parseAlign() error {
+	tok, err := p.value("align")
+	if err != nil {
+		return err
+	}
+
+	grid, offsetText, hasOffset := strings.Cut(tok.text, "+")
+	var offset time.Duration
+	if hasOffset {
+		if offset, err = time.ParseDuration(offsetText); err != nil || offset < 0 {
+			return p.errorAt(tok.offset+len(grid)+1, "invalid duration %q", offsetText)
+		}
+	}
+
+	if strings.EqualFold(grid, "clock") {
+		p.opts = append(p.opts, AlignToClock(offset))
+		return nil
+	}
+	anchor, ok := p.parseTime(grid)
+	if !ok {
+		return p.errorAt(tok.offset, "invalid alignment %q", grid)
+	}
+	p.opts = append(p.opts, AlignToAnchor(anchor, offset))
+	return nil
+}
+
+func (p *dslParser) parseJitter() error {
+	tok, err := p.value("jitter")
+	if err != nil {
+		return err
+	}
+
+	d, err := time.ParseDuration(tok.text)
+	if err != nil || d < 0 {
+		return p.errorAt(tok.offset, "invalid duration %q", tok.text)
+	}
+	p.opts = append(p.opts, SetJitter(d))
+	return nil
+}
+
+func (p *dslParser) parseSplay() error {
+	tok, err := p.value("splay")
+	if err != nil {
+		return err
+	}
+
+	// The key may itself contain "@", so only the first one separates it
+	maxDelay, key, _ := strings.Cut(tok.text, "@")
+	d, err := time.ParseDuration(maxDelay)
+	if err != nil || d < 0 {
+		return p.errorAt(tok.offset, "invalid duration %q", maxDelay)
+	}
+	if strings.HasPrefix(key, `"`) {
+		quoted := key
+		if key, err = strconv.Unquote(quoted); err != nil {
+			return p.errorAt(tok.offset+len(maxDelay)+1, "invalid splay key %s", quoted)
+		}
+	}
+	p.opts = append(p.opts, SetSplay(key, d))
+	return nil
+}
+
+func (p *dslParser) parseRuns(keyword string) error {
+	tok, err := p.value(keyword)
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(tok.text)
+	if err != nil || n < 0 {
+		return p.errorAt(tok.offset, "invalid run count %q", tok.text)
+	}
+
+	if keyword == "limit" {
+		// Must run before SetRemainingRuns, which it would reset
+		p.opts = append([]ScheduleOption{SetMaxRuns(n)}, p.opts...)
+	} else {
+		p.opts = append(p.opts, SetRemainingRuns(n))
+	}
+	return nil
+}
+
+// parseWindows parses comma-separated START-END time windows.
+func (p *dslParser) parseWindows(tok dslToken) ([]TimeWindow, error) {
+	var windows []TimeWindow
+	for _, part := range tokenize(tok.text, tok.offset, isRune(',')) {
+		start, end, ok := strings.Cut(part.text, "-")
+		if !ok {
+			return nil, p.errorAt(part.offset, "expected START-END, got %q", part.text)
+		}
+
+		w := TimeWindow{}
+		startTime, err := p.parseClock(dslToken{text: start, offset: part.offset})
+		if err != nil {
+			return nil, err
+		}
+		w.Start = *startTime
+
+		if end != "24:00" {
+			endTime, err := p.parseClock(dslToken{text: end, offset: part.offset + len(start) + 1})
+			if err != nil {
+				return nil, err
+			}
+			w.End = *endTime
+		}
+		windows = append(windows, w)
+	}
+
+	if len(windows) == 0 {
+		return nil, p.errorAt(tok.offset, "missing time window")
+	}
+	return windows, nil
+}
+
+func (p *dslParser) parseClock(tok dslToken) (*time.Time, error) {
+	t, err := parseConfigClock("", tok.text, p.loc)
+	if err != nil || t == nil {
+		return nil, p.errorAt(tok.offset, "invalid time of day %q", tok.text)
+	}
+	return t, nil
+}
+
+// parseWeekdays parses comma-separated weekdays and ranges such as
+// "mon-fri,sun". Ranges may wrap around the end of the week.
+func (p *dslParser) parseWeekdays(tok dslToken) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, part := range tokenize(tok.text, tok.offset, isRune(',')) {
+		from, to, isRange := strings.Cut(part.text, "-")
+
+		first, err := p.parseWeekday(dslToken{text: from, offset: part.offset})
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			last, err = p.parseWeekday(dslToken{text: to, offset: part.offset + len(from) + 1})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for day := first; ; day = (day + 1) % 7 {
+			weekdays = append(weekdays, day)
+			if day == last {
+				break
+			}
+		}
+	}
+
+	if len(weekdays) == 0 {
+		return nil, p.errorAt(tok.offset, "missing weekdays")
+	}
+	return weekdays, nil
+}
+
+// parseWeekday parses a weekday name or an abbreviation of at least three
+// letters.
+func (p *dslParser) parseWeekday(tok dslToken) (time.Weekday, error) {
+	name := strings.ToLower(tok.text)
+	if len(name) >= 3 {
+		for _, day := range dslWeekdays {
+			if strings.HasPrefix(strings.ToLower(day.String()), name) {
+				return day, nil
+			}
+		}
+	}
+	return 0, p.errorAt(tok.offset, "unknown weekday %q", tok.text)
+}
+
+func (p *dslParser) errorAt(offset int, format string, args ...interface{}) error {
+	return &ParseError{Input: p.input, Column: offset + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits text at runes matching sep, keeping the offset of each
+// non-empty part relative to base. Double-quoted strings, with backslash
+// escapes, are never split.
+func tokenize(text string, base int, sep func(rune) bool) []dslToken {
+	var tokens []dslToken
+	start := -1
+	quoted, escaped := false, false
+	for i, r := range text {
+		if quoted {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				quoted = false
+			}
+			continue
+		}
+		quoted = r == '"'
+
+		switch {
+		case sep(r) && start >= 0:
+			tokens = append(tokens, dslToken{text: text[start:i], offset: base + start})
+			start = -1
+		case !sep(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, dslToken{text: text[start:], offset: base + start})
+	}
+	return tokens
+}
+
+// isRune returns a separator function matching r.
+func isRune(r rune) func(rune) bool {
+	return func(c rune) bool { return c == r }
+}
+
+// String returns the canonical text form of the schedule, which Parse turns
+// back into an equivalent schedule. Hooks, the calendar, the cached next run
+// and an end time set without a start time, which has no effect, are not
+// included.
+//
+// Example:
+//
+//	every 30m between 09:00-17:00 on mon-fri from 2025-01-06 tz=Asia/Jakarta
+func (s *Schedule) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loc := s.configLocation()
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	unit := s.intervalTimeUnit.String()
+	for _, u := range dslUnits {
+		if u.unit == s.intervalTimeUnit {
+			unit = u.names[0]
+		}
+	}
+	clauses := []string{fmt.Sprintf("every %d%s", s.interval, unit)}
+
+	switch {
+	case s.startTime != nil:
+		// An end time alone opens no window, so it has no text form
+		w := TimeWindow{Start: *s.startTime}
+		if s.endTime != nil {
+			w.End = *s.endTime
+		}
+		clauses = append(clauses, "between "+formatDSLWindows([]TimeWindow{w}, loc))
+	case len(s.timeWindows) > 0:
+		clauses = append(clauses, "between "+formatDSLWindows(s.timeWindows, loc))
+	case s.weeklyWindows != nil:
+		clauses = append(clauses, "weekly "+formatDSLWeeklyWindows(s.weeklyWindows, loc))
+	}
+
+	if s.allowedWeekdays != nil {
+		clauses = append(clauses, "on "+formatDSLWeekdays(func(day time.Weekday) bool {
+			return (*s.allowedWeekdays)[day]
+		}))
+	}
+	if s.startDate != nil {
+		clauses = append(clauses, "from "+formatDSLDate(s.startDate.In(loc)))
+	}
+	if s.endDate != nil {
+		clauses = append(clauses, "until "+formatDSLDate(s.endDate.In(loc)))
+	}
+	if s.maxRuns > 0 {
+		clauses = append(clauses, "limit "+strconv.Itoa(s.maxRuns))
+		if s.remainingRuns != s.maxRuns {
+			clauses = append(clauses, "remaining "+strconv.Itoa(s.remainingRuns))
+		}
+	}
+	switch {
+	case s.pausedUntil != nil:
+		clauses = append(clauses, "paused-until "+formatDSLDate(s.pausedUntil.In(loc)))
+	case !s.enabled:
+		clauses = append(clauses, "disabled")
+	}
+	if s.disabledRecheck != 0 {
+		clauses = append(clauses, "recheck "+formatDSLDuration(s.disabledRecheck))
+	}
+	if s.disabledMode == DisabledSleep {
+		clauses = append(clauses, "sleep")
+	}
+	if !s.precision {
+		clauses = append(clauses, "imprecise")
+	}
+	if s.nthDay != 0 {
+		clauses = append(clauses, "nth "+strconv.Itoa(s.nthDay))
+	}
+	if s.monthEnd != MonthEndRollover {
+		clauses = append(clauses, "month-end "+s.monthEnd.String())
+	}
+	if s.alignMode != AlignNone {
+		grid := "clock"
+		if s.alignMode == AlignAnchor {
+			grid = formatDSLDate(s.alignAnchor.In(loc))
+		}
+		if s.alignOffset != 0 {
+			grid += "+" + formatDSLDuration(s.alignOffset)
+		}
+		clauses = append(clauses, "align "+grid)
+	}
+	if s.jitter != 0 {
+		clauses = append(clauses, "jitter "+formatDSLDuration(s.jitter))
+	}
+	if s.splayMax != 0 {
+		splay := formatDSLDuration(s.splayMax)
+		if s.splayKey != "" {
+			splay += "@" + formatDSLKey(s.splayKey)
+		}
+		clauses = append(clauses, "splay "+splay)
+	}
+	if s.dstGap == DSTSkip {
+		clauses = append(clauses, "dst-skip")
+	}
+	if s.dstOverlap == DSTRunTwice {
+		clauses = append(clauses, "dst-twice")
+	}
+	if s.location != nil {
+		clauses = append(clauses, "pinned")
+	}
+	if loc != time.UTC {
+		clauses = append(clauses, "tz="+loc.String())
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+// formatDSLWindows formats time windows as "09:00-12:00,13:00-24:00".
+func formatDSLWindows(windows []TimeWindow, loc *time.Location) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		end := "24:00"
+		if !w.End.IsZero() {
+			end = formatDSLClock(w.End.In(loc))
+		}
+		parts[i] = formatDSLClock(w.Start.In(loc)) + "-" + end
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatDSLWeeklyWindows formats a weekly table, grouping consecutive days
+// with the same windows, such as "mon-fri=09:00-18:00;sat=10:00-14:00".
+func formatDSLWeeklyWindows(table map[time.Weekday][]TimeWindow, loc *time.Location) string {
+	var entries []string
+	done := make(map[time.Weekday]bool)
+	for _, day := range dslWeekdays {
+		if done[day] || len(table[day]) == 0 {
+			continue
+		}
+
+		windows := formatDSLWindows(table[day], loc)
+		days := formatDSLWeekdays(func(other time.Weekday) bool {
+			same := len(table[other]) > 0 && formatDSLWindows(table[other], loc) == windows
+			done[other] = done[other] || same
+			return same
+		})
+		entries = append(entries, days+"="+windows)
+	}
+	return strings.Join(entries, ";")
+}
+
+// formatDSLWeekdays formats the weekdays matching allowed as comma-separated
+// names and ranges in Monday-first order, such as "mon-wed,fri".
+func formatDSLWeekdays(allowed func(time.Weekday) bool) string {
+	var parts []string
+	for i := 0; i < len(dslWeekdays); i++ {
+		if !allowed(dslWeekdays[i]) {
+			continue
+		}
+
+		j := i
+		for j+1 < len(dslWeekdays) && allowed(dslWeekdays[j+1]) {
+			j++
+		}
+		part := formatDSLWeekday(dslWeekdays[i])
+		if j > i {
+			part += "-" + formatDSLWeekday(dslWeekdays[j])
+		}
+		parts = append(parts, part)
+		i = j
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatDSLWeekday returns the three-letter name of day, such as "mon".
+func formatDSLWeekday(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+// formatDSLClock formats a time of day as "15:04", or "15:04:05" when it has
+// seconds and "15:04:05.5" when it has fractions of a second.
+func formatDSLClock(t time.Time) string {
+	if t.Second() != 0 || t.Nanosecond() != 0 {
+		return t.Format(clockLayout)
+	}
+	return t.Format("15:04")
+}
+
+// formatDSLKey returns key as is, or quoted when it has spaces, quotes or
+// unprintable runes that Parse would not read back.
+func formatDSLKey(key string) string {
+	special := func(r rune) bool { return r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) }
+	if strings.IndexFunc(key, special) >= 0 {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+// formatDSLDuration formats a duration like time.Duration.String, dropping
+// zero minutes and seconds, such as "1h" or "30s".
+func formatDSLDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// formatDSLDate formats a date as "2006-01-02", adding the time of day when it
+// is not midnight.
+func formatDSLDate(t time.Time) string {
+	if t.Equal(startOfDay(t)) {
+		return t.Format(dateLayout)
+	}
+	return t.Format(dateLayout) + "T" + formatDSLClock(t)
+}
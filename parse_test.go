@@ -0,0 +1,249 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	s, err := Parse("every 30m between 09:00-17:00 on mon-fri from 2025-01-06 tz=Asia/Jakarta")
+	require.NoError(t, err)
+
+	assert.Equal(t, 30, s.interval)
+	assert.Equal(t, Minute, s.intervalTimeUnit)
+	require.NotNil(t, s.startTime)
+	assert.Equal(t, time.Date(2000, 1, 1, 9, 0, 0, 0, jakarta), *s.startTime)
+	require.NotNil(t, s.endTime)
+	assert.Equal(t, time.Date(2000, 1, 1, 17, 0, 0, 0, jakarta), *s.endTime)
+	require.NotNil(t, s.startDate)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, jakarta), *s.startDate)
+	assert.Equal(t, map[time.Weekday]bool{
+		time.Monday: true, time.Tuesday: true, time.Wednesday: true,
+		time.Thursday: true, time.Friday: true,
+	}, *s.allowedWeekdays)
+
+	// Saturday is skipped
+	saturday := time.Date(2025, 1, 11, 10, 0, 0, 0, jakarta)
+	assert.Equal(t, time.Date(2025, 1, 13, 9, 0, 0, 0, jakarta), s.Next(saturday).In(jakarta))
+}
+
+func TestParse_Clauses(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, s *Schedule)
+	}{
+		{
+			name:  "long unit",
+			input: "every 2 hours",
+			check: func(t *testing.T, s *Schedule) {
+				assert.Equal(t, 2, s.interval)
+				assert.Equal(t, Hour, s.intervalTimeUnit)
+			},
+		},
+		{
+			name:  "open-ended window",
+			input: "every 1h between 21:00-24:00",
+			check: func(t *testing.T, s *Schedule) {
+				require.NotNil(t, s.startTime)
+				assert.Nil(t, s.endTime)
+			},
+		},
+		{
+			name:  "multiple windows",
+			input: "every 15m between 08:00-12:00,22:00-02:00",
+			check: func(t *testing.T, s *Schedule) {
+				require.Len(t, s.timeWindows, 2)
+				assert.Equal(t, 2, s.timeWindows[1].End.Hour())
+			},
+		},
+		{
+			name:  "weekly windows",
+			input: "every 1h weekly mon-fri=09:00-18:00;sat=10:00-14:00",
+			check: func(t *testing.T, s *Schedule) {
+				assert.Len(t, s.weeklyWindows, 6)
+				assert.Equal(t, 10, s.weeklyWindows[time.Saturday][0].Start.Hour())
+				assert.Empty(t, s.weeklyWindows[time.Sunday])
+			},
+		},
+		{
+			name:  "wrapping weekday range",
+			input: "every 1d on fri-mon,wednesday",
+			check: func(t *testing.T, s *Schedule) {
+				assert.Equal(t, map[time.Weekday]bool{
+					time.Friday: true, time.Saturday: true, time.Sunday: true,
+					time.Monday: true, time.Wednesday: true,
+				}, *s.allowedWeekdays)
+			},
+		},
+		{
+			name:  "dates runs and flags",
+			input: "EVERY 1d until 2025-06-30T18:00 limit 10 remaining 4 disabled imprecise",
+			check: func(t *testing.T, s *Schedule) {
+				require.NotNil(t, s.endDate)
+				assert.Equal(t, time.Date(2025, 6, 30, 18, 0, 0, 0, time.UTC), *s.endDate)
+				assert.Equal(t, 10, s.maxRuns)
+				assert.Equal(t, 4, s.remainingRuns)
+				assert.False(t, s.enabled)
+				assert.False(t, s.precision)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.input)
+			require.NoError(t, err)
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		column int
+	}{
+		{name: "missing every", input: "between 09:00-17:00", column: 1},
+		{name: "invalid interval", input: "every m", column: 7},
+		{name: "unknown unit", input: "every 30x", column: 9},
+		{name: "missing value", input: "every", column: 6},
+		{name: "unknown clause", input: "every 1h sometimes", column: 10},
+		{name: "duplicate clause", input: "every 1h every 2h", column: 10},
+		{name: "bad clock", input: "every 1h between 08:00-12:00,13:0x-17:00", column: 30},
+		{name: "bad end clock", input: "every 1h between 08:00-25:00", column: 24},
+		{name: "window without end", input: "every 1h between 08:00", column: 18},
+		{name: "unknown weekday", input: "every 1d on mon-fry", column: 17},
+		{name: "weekly without windows", input: "every 1h weekly mon", column: 17},
+		{name: "invalid date", input: "every 1d from 06/01/2025", column: 15},
+		{name: "invalid runs", input: "every 1d limit -1", column: 16},
+		{name: "invalid duration", input: "every 1h recheck soon", column: 18},
+		{name: "unknown month end", input: "every 1mo month-end round", column: 21},
+		{name: "invalid nth day", input: "every 1mo nth 0", column: 15},
+		{name: "invalid alignment", input: "every 1h align noon", column: 16},
+		{name: "invalid align offset", input: "every 1h align clock+7", column: 22},
+		{name: "invalid jitter", input: "every 1h jitter -5s", column: 17},
+		{name: "invalid splay", input: "every 1h splay web-1", column: 16},
+		{name: "unterminated splay key", input: `every 1h splay 5m@"web 1`, column: 19},
+		{name: "unknown time zone", input: "every 1d tz=Mars/Olympus", column: 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.column, parseErr.Column, parseErr.Error())
+			assert.Equal(t, tt.input, parseErr.Input)
+			assert.ErrorIs(t, err, ErrInvalidScheduleString)
+		})
+	}
+
+	// Well-formed strings still go through validation
+	_, err := Parse("every 0m")
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+	_, err = Parse("every 2w on mon")
+	assert.ErrorIs(t, err, ErrMultiIntervalWithWeekdayWindow)
+}
+
+func TestSchedule_String(t *testing.T) {
+	inputs := []string{
+		"every 30m between 09:00-17:00 on mon-fri from 2025-01-06 tz=Asia/Jakarta",
+		"every 5s",
+		"every 1h between 08:00-12:00,22:00-02:00:30",
+		"every 1h between 21:00-24:00",
+		"every 1h weekly mon-wed,fri=09:00-18:00;thu=09:00-12:00;sat=10:00-24:00",
+		"every 1d on mon,wed,fri-sun until 2025-06-30T18:00 limit 10 remaining 4 disabled imprecise",
+		"every 2mo from 2025-01-31T09:30 tz=Europe/Berlin",
+		"every 1y limit 3",
+		"every 1h paused-until 2025-01-06T06:00 recheck 30s",
+		"every 1d disabled recheck 1h30m",
+		"every 15m between 09:00-17:00 disabled sleep",
+		"every 1h between 09:00-17:00 on mon-fri pinned tz=Asia/Jakarta",
+		"every 1d pinned",
+		"every 1d between 02:30-04:00 dst-skip dst-twice tz=America/New_York",
+		"every 1mo from 2025-01-31 month-end clamp",
+		"every 1mo on mon-fri nth -1",
+		"every 15m align clock",
+		"every 1h between 09:00-17:00 align clock+7m",
+		"every 90m align 2025-01-06T09:00+5m tz=Asia/Jakarta",
+		"every 1h jitter 30s splay 5m@web-1",
+		"every 1d splay 2h",
+		`every 1d limit 3 splay 2h@"web 1"`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			s, err := Parse(input)
+			require.NoError(t, err)
+			assert.Equal(t, input, s.String())
+		})
+	}
+
+	// Schedules built with options print their canonical form
+	startTime := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+	s, err := New(2, Week, SetStartTime(&startTime), SetMaxRuns(5))
+	require.NoError(t, err)
+	assert.Equal(t, "every 2w between 09:00-24:00 limit 5", s.String())
+}
+
+func TestSchedule_StringRoundTrip(t *testing.T) {
+	start := time.Date(2025, 1, 6, 9, 0, 0, 500000000, time.UTC)
+	end := time.Date(2025, 1, 6, 9, 15, 0, 750000000, time.UTC)
+	opens := time.Date(0, 1, 1, 9, 0, 0, 500000000, time.UTC)
+	closes := time.Date(0, 1, 1, 16, 59, 59, 999999999, time.UTC)
+
+	tests := []struct {
+		name string
+		opts []ScheduleOption
+		from time.Time
+	}{
+		{
+			name: "splay key with punctuation",
+			opts: []ScheduleOption{SetSplay("web-1.eu:8080", 5*time.Minute)},
+			from: parseTime(t, "2025-01-06 09:00:00"),
+		},
+		{
+			name: "splay key with spaces and quotes",
+			opts: []ScheduleOption{SetSplay("web 1\t\"eu\"", 5*time.Minute)},
+			from: parseTime(t, "2025-01-06 09:00:00"),
+		},
+		{
+			name: "sub-second dates",
+			opts: []ScheduleOption{SetStartDate(&start), SetEndDate(&end)},
+			from: time.Date(2025, 1, 6, 9, 0, 0, 200000000, time.UTC),
+		},
+		{
+			name: "end time without a start time",
+			opts: []ScheduleOption{SetEndTime(&closes)},
+			from: parseTime(t, "2025-01-06 16:55:00"),
+		},
+		{
+			name: "sub-second time window",
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: opens, End: closes}),
+			},
+			from: parseTime(t, "2025-01-06 08:59:00"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, err := New(15, Minute, tt.opts...)
+			require.NoError(t, err)
+
+			parsed, err := Parse(original.String())
+			require.NoError(t, err)
+			assert.Equal(t, original.String(), parsed.String())
+			assert.Equal(t, original.NextN(tt.from, 3), parsed.NextN(tt.from, 3))
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package robfigcronschedule
+
+import "time"
+
+// Prev returns the latest scheduled run strictly before t, or the zero time if
+// the schedule has no run before t. It is the counterpart of Next and follows
+// the same rules for the start and end date, allowed weekdays, calendar
+// dates, time windows and precision mode, without calling hooks or touching
+// the nextRun cache.
+//
+// The first run is the one Next returns before the start date. With a start
+// date and a calendar unit (Day, Week, Month, Year) the runs are followed from
+// there. Otherwise runs are assumed to follow the steady state Next settles
+// into: within each time window they are spaced by the interval from the
+// window's start, or from midnight without a window, or lie on the alignment
+// grid set by AlignToClock or AlignToAnchor. Whether the schedule is
+// enabled, the run limit and time ranges blocked by a RangeCalendar are not
+// taken into account.
+//
+// Example:
+//
+//	// Catch up if the last run was missed
+//	if last := schedule.Prev(time.Now()); last.After(lastProcessed) {
+//	    process(last)
+//	}
+func (s *Schedule) Prev(t time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.location != nil {
+		return inLocation(s.prev(t.In(s.location)), t.Location())
+	}
+	return s.prev(t)
+}
+
+// prev computes Prev. Callers must hold s.mu.
+func (s *Schedule) prev(t time.Time) time.Time {
+	// Runs after the end date never happen
+	if s.endDate != nil && t.After(*s.endDate) {
+		t = s.endDate.Add(time.Nanosecond).In(t.Location())
+	}
+
+	var first time.Time
+	if s.startDate != nil {
+		first = s.compute(s.startDate.In(t.Location()).Add(-time.Nanosecond))
+		if !first.Before(t) {
+			return time.Time{}
+		}
+	}
+
+	// Calendar units drift from any daily grid, so follow the chain of runs
+	// from the first one instead
+	if !first.IsZero() && s.fixedInterval() == 0 {
+		if prev, ok := s.lastRunFrom(first, t); ok {
+			return prev
+		}
+	}
+	if s.nthDay != 0 {
+		return s.prevNthDay(t)
+	}
+
+	var prev time.Time
+	current := startOfDay(t)
+	for rejected, blocked := 0, 0; rejected < maxScanDays && blocked < maxBlockedDays; {
+		if !s.isDayAllowed(current) {
+			if s.isBlocked(current) {
+				blocked++
+			} else {
+				rejected++
+			}
+		} else {
+			rejected++
+			for _, p := range s.periodsOn(current) {
+				if candidate := s.lastStepBefore(p, first, t); candidate.After(prev) {
+					prev = candidate
+				}
+			}
+		}
+
+		// Windows that opened the day before may still reach past a run
+		// found today, so look one more day back before stopping.
+		if !prev.IsZero() && current.Before(startOfDay(prev)) {
+			break
+		}
+		if !first.IsZero() && current.Before(startOfDay(first)) {
+			break
+		}
+		current = current.AddDate(0, 0, -1)
+	}
+
+	if prev.Before(first) {
+		return first
+	}
+	return prev
+}
+
+// lastRunFrom follows the runs from first, as NextN does, and returns the last
+// one before t. ok is false if t is more than maxPreviewOccurrences runs away.
+func (s *Schedule) lastRunFrom(first, t time.Time) (last time.Time, ok bool) {
+	last = first
+	for i := 0; i < maxPreviewOccurrences; i++ {
+		next := s.next(last)
+		if !next.IsZero() && !next.After(last) {
+			next = s.next(last.Add(time.Nanosecond))
+		}
+		if next.IsZero() || !next.Before(t) || !next.After(last) {
+			return last, true
+		}
+		last = next
+	}
+	return time.Time{}, false
+}
+
+// periodsOn returns the spans in which runs are spaced by the interval on day:
+// the time windows that open on day, or the whole day without a window.
+func (s *Schedule) periodsOn(day time.Time) []span {
+	if s.hasWindows() {
+		return s.windowsOn(day)
+	}
+	return []span{{open: startOfDay(day), end: endOfDay(day)}}
+}
+
+// lastStepBefore returns the last run inside p that lies before t, spacing
+// runs by the interval from p's open, or from first when first falls inside
+// p, or p's reopen if that is later. Returns the zero time if there is none.
+func (s *Schedule) lastStepBefore(p span, first, t time.Time) time.Time {
+	last := s.lastIntervalBefore(p, first, t)
+	if p.reopen.After(last) && p.reopen.Before(t) && !p.reopen.After(p.end) {
+		return p.reopen
+	}
+	return last
+}
+
+// lastIntervalBefore is lastStepBefore without p's reopen.
+func (s *Schedule) lastIntervalBefore(p span, first, t time.Time) time.Time {
+	anchor := s.alignStart(p.open)
+	if !first.IsZero() && !first.Before(p.open) && !first.After(p.end) {
+		anchor = first
+	}
+
+	limit := p.end
+	if !t.After(limit) {
+		limit = t.Add(-time.Nanosecond)
+	}
+	if limit.Before(anchor) {
+		return time.Time{}
+	}
+
+	if step := s.fixedInterval(); step > 0 {
+		return anchor.Add(limit.Sub(anchor) / step * step)
+	}
+
+	last := anchor
+	for next := s.incrementInterval(last); !next.After(limit); next = s.incrementInterval(last) {
+		last = next
+	}
+	return last
+}
+
+// fixedInterval returns the interval as a duration for units of fixed length,
+// or 0 for calendar units such as days and months.
+func (s *Schedule) fixedInterval() time.Duration {
+	switch s.intervalTimeUnit {
+	case Second:
+		return time.Duration(s.interval) * time.Second
+	case Minute:
+		return time.Duration(s.interval) * time.Minute
+	case Hour:
+		return time.Duration(s.interval) * time.Hour
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,174 @@
+package robfigcronschedule
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Prev(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		t        string
+		expected string
+	}{
+		{
+			name:     "plain interval from midnight",
+			input:    "every 7m",
+			t:        "2025-01-08 10:03:00",
+			expected: "2025-01-08 10:02:00",
+		},
+		{
+			name:     "inside window",
+			input:    "every 3h between 09:00-17:00",
+			t:        "2025-01-08 13:00:00",
+			expected: "2025-01-08 12:00:00",
+		},
+		{
+			name:     "exactly on a run",
+			input:    "every 3h between 09:00-17:00",
+			t:        "2025-01-08 12:00:00",
+			expected: "2025-01-08 09:00:00",
+		},
+		{
+			name:     "before window uses previous allowed day",
+			input:    "every 3h between 09:00-17:00 on mon-fri",
+			t:        "2025-01-13 08:00:00",
+			expected: "2025-01-10 15:00:00",
+		},
+		{
+			name:     "overnight window opened yesterday",
+			input:    "every 2h between 22:00-03:00 on fri",
+			t:        "2025-01-11 02:30:00",
+			expected: "2025-01-11 02:00:00",
+		},
+		{
+			name:     "second window",
+			input:    "every 50m between 08:00-12:00,13:00-17:00 imprecise",
+			t:        "2025-01-08 12:59:00",
+			expected: "2025-01-08 11:20:00",
+		},
+		{
+			name:     "daily unit",
+			input:    "every 1d on tue,thu",
+			t:        "2025-01-13 12:00:00",
+			expected: "2025-01-09 00:00:00",
+		},
+		{
+			name:     "monthly from start date",
+			input:    "every 1mo from 2025-01-15T09:00",
+			t:        "2025-01-20 00:00:00",
+			expected: "2025-01-15 09:00:00",
+		},
+		{
+			name:     "monthly after several runs",
+			input:    "every 1mo from 2025-01-15T09:00",
+			t:        "2025-04-01 00:00:00",
+			expected: "2025-03-15 00:00:00",
+		},
+		{
+			name:     "first day anchored at start date",
+			input:    "every 10m from 2025-01-08T10:17",
+			t:        "2025-01-08 10:40:00",
+			expected: "2025-01-08 10:37:00",
+		},
+		{
+			name:     "before start date",
+			input:    "every 10m from 2025-01-08T10:17",
+			t:        "2025-01-08 10:17:00",
+			expected: "",
+		},
+		{
+			name:     "after end date",
+			input:    "every 10m until 2025-01-08T10:15",
+			t:        "2025-02-01 00:00:00",
+			expected: "2025-01-08 10:10:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.input)
+			require.NoError(t, err)
+
+			var expected time.Time
+			if tt.expected != "" {
+				expected = parseTime(t, tt.expected)
+			}
+			assert.Equal(t, expected, s.Prev(parseTime(t, tt.t)))
+		})
+	}
+}
+
+func TestSchedule_PrevProperties(t *testing.T) {
+	tests := []struct {
+		input string
+		// inverse reports whether Prev(Next(p)) == p holds. Multi-day intervals
+		// depend on the day the chain started, so there it does not.
+		inverse bool
+	}{
+		{input: "every 7m", inverse: true},
+		{input: "every 45m on mon,wed,fri", inverse: true},
+		{input: "every 3h between 09:00-17:00 on mon-fri", inverse: true},
+		{input: "every 3h between 09:00-17:00 on mon-fri imprecise", inverse: true},
+		{input: "every 50m between 08:00-12:00,22:00-02:00 imprecise", inverse: true},
+		{input: "every 1h weekly mon-fri=09:30-18:00;sat=10:00-14:00", inverse: true},
+		{input: "every 2h from 2025-01-08T10:17 until 2025-01-20T12:00", inverse: true},
+		{input: "every 90m between 08:00-20:00 tz=Asia/Jakarta", inverse: true},
+		{input: "every 1d on tue,thu", inverse: true},
+		{input: "every 2d between 09:00-10:00"},
+		{input: "every 1w from 2025-01-07T09:00"},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	base := parseTime(t, "2025-01-06 00:00:00")
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			s, err := Parse(tt.input)
+			require.NoError(t, err)
+
+			for i := 0; i < 500; i++ {
+				now := base.Add(time.Duration(rng.Int63n(int64(21 * 24 * time.Hour))))
+				prev := s.Prev(now)
+				if prev.IsZero() {
+					continue
+				}
+				require.True(t, prev.Before(now), "Prev(%v) = %v", now, prev)
+
+				// No run between Prev(t) and t
+				runs := s.NextN(prev, 1)
+				if len(runs) == 0 {
+					continue
+				}
+				require.False(t, runs[0].Before(now), "Next(Prev(%v)) = %v", now, runs[0])
+
+				if tt.inverse {
+					require.Equal(t, prev, s.Prev(runs[0]), "Prev(Next(%v))", prev)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedule_PrevMatchesNext(t *testing.T) {
+	s, err := Parse("every 40m between 09:00-17:00 on mon-fri")
+	require.NoError(t, err)
+	live, err := Parse("every 40m between 09:00-17:00 on mon-fri")
+	require.NoError(t, err)
+
+	// Walking forward with Next and backward with Prev visits the same runs
+	var forward []time.Time
+	for current := parseTime(t, "2025-01-09 08:00:00"); len(forward) < 40; {
+		current = live.Next(current)
+		forward = append(forward, current)
+	}
+
+	for i := len(forward) - 1; i > 0; i-- {
+		assert.Equal(t, forward[i-1], s.Prev(forward[i]))
+	}
+}
@@ -0,0 +1,126 @@
+package robfigcronschedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// NextN returns the next n run times after t, in order, without calling hooks
+// or updating the nextRun cache. Fewer than n times are returned when the
+// schedule ends first (end date or run limit). A disabled schedule has no
+// upcoming runs, and a paused one has none before its pause ends.
+//
+// Example:
+//
+//	// Show the next 10 runs
+//	for _, run := range schedule.NextN(time.Now(), 10) {
+//	    fmt.Println(run)
+//	}
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var runs []time.Time
+	if n < 1 {
+		return runs
+	}
+
+	s.forEachRun(t, func(run time.Time) bool {
+		runs = append(runs, run)
+		return len(runs) < n
+	})
+	return runs
+}
+
+// Between returns every run time after from and up to and including to, in
+// order, without calling hooks or updating the nextRun cache. As with Next,
+// each run is computed from the previous one, starting at from.
+//
+// At most maxPreviewOccurrences (10000) runs are returned. If the range holds
+// more, the first ones are returned together with ErrTooManyOccurrences.
+//
+// Example:
+//
+//	// All runs this week
+//	runs, err := schedule.Between(monday, monday.AddDate(0, 0, 7))
+func (s *Schedule) Between(from, to time.Time) ([]time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var runs []time.Time
+	var err error
+	s.forEachRun(from, func(run time.Time) bool {
+		if run.After(to) {
+			return false
+		}
+		if len(runs) == maxPreviewOccurrences {
+			err = fmt.Errorf("%w: more than %d runs", ErrTooManyOccurrences, maxPreviewOccurrences)
+			return false
+		}
+		runs = append(runs, run)
+		return true
+	})
+	return runs, err
+}
+
+// forEachRun calls fn with each upcoming run after t, in order, until fn
+// returns false or the schedule ends. It follows steps 2-9 of Next, starting
+// with the cached next run if it is still ahead of t, but changes no state.
+// Callers must hold s.mu.
+func (s *Schedule) forEachRun(t time.Time, fn func(time.Time) bool) {
+	// Evaluate in the schedule's own location and answer in t's
+	caller := t.Location()
+	if s.location != nil {
+		t = t.In(s.location)
+	}
+
+	if !s.enabled {
+		if s.pausedUntil == nil {
+			return
+		}
+		// A paused schedule runs again once Next reaches the end of the pause
+		if t.Before(*s.pausedUntil) {
+			t = s.pausedUntil.In(t.Location())
+		}
+	}
+
+	remaining, lastRun := s.remainingRuns, s.lastRun
+	monthDay := s.monthDay
+	defer func() { s.monthDay = monthDay }()
+	s.anchorMonthDay(t)
+
+	current := t
+	for {
+		var next time.Time
+		// A cached run delayed by the jitter is not part of the plain runs
+		if current.Equal(t) && s.nextRun.After(t) && !s.nextRun.Equal(s.jitterRun) {
+			next = s.nextRun.In(t.Location())
+		} else {
+			next = s.next(current)
+		}
+
+		// Non-precision mode rounds up to the window grid, so a time on the
+		// grid is its own next run. robfig/cron asks again a moment later.
+		if !next.IsZero() && !next.After(current) {
+			next = s.next(current.Add(time.Nanosecond))
+		}
+
+		// Stop at the end of the schedule, and if no progress is made
+		if next.IsZero() || s.isPastEndDate(next) || !next.After(current) {
+			return
+		}
+
+		if s.maxRuns > 0 && !next.Equal(lastRun) {
+			if remaining < 1 {
+				return
+			}
+			remaining--
+			lastRun = next
+		}
+
+		if !fn(next.In(caller)) {
+			return
+		}
+		current = next
+	}
+}
@@ -0,0 +1,144 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_NextN(t *testing.T) {
+	s, err := Parse("every 3h between 09:00-17:00 on mon-fri")
+	require.NoError(t, err)
+
+	friday := parseTime(t, "2025-01-10 14:00:00")
+	assert.Equal(t, []time.Time{
+		parseTime(t, "2025-01-10 17:00:00"),
+		parseTime(t, "2025-01-13 09:00:00"),
+		parseTime(t, "2025-01-13 12:00:00"),
+		parseTime(t, "2025-01-13 15:00:00"),
+	}, s.NextN(friday, 4))
+
+	assert.Empty(t, s.NextN(friday, 0))
+}
+
+func TestSchedule_NextNPaused(t *testing.T) {
+	s, err := Parse("every 2h paused-until 2025-01-10T13:00")
+	require.NoError(t, err)
+
+	// No runs before the pause ends
+	assert.Equal(t, []time.Time{
+		parseTime(t, "2025-01-10 15:00:00"),
+		parseTime(t, "2025-01-10 17:00:00"),
+	}, s.NextN(parseTime(t, "2025-01-10 09:00:00"), 2))
+
+	require.NoError(t, s.Set(Disable()))
+	assert.Empty(t, s.NextN(parseTime(t, "2025-01-10 09:00:00"), 2))
+}
+
+func TestSchedule_NextNMatchesNext(t *testing.T) {
+	inputs := []string{
+		"every 45m between 08:00-12:00,22:00-02:00 on mon,wed,fri",
+		"every 1h weekly mon-fri=09:00-18:00;sat=10:00-14:00 imprecise",
+		"every 1d on tue,thu from 2025-02-01",
+		"every 1mo from 2025-01-31T09:30",
+		"every 20m until 2025-01-08T10:00 limit 50",
+	}
+
+	start := parseTime(t, "2025-01-06 07:10:00")
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			preview, err := Parse(input)
+			require.NoError(t, err)
+			live, err := Parse(input)
+			require.NoError(t, err)
+
+			var expected []time.Time
+			for current := start; len(expected) < 30; {
+				next := live.Next(current)
+				if next.Equal(current) {
+					// Non-precision mode, see forEachRun
+					next = live.Next(current.Add(time.Nanosecond))
+				}
+				if next.IsZero() {
+					break
+				}
+				expected = append(expected, next)
+				current = next
+			}
+
+			assert.Equal(t, expected, preview.NextN(start, 30))
+		})
+	}
+}
+
+func TestSchedule_PreviewHasNoSideEffects(t *testing.T) {
+	hookCalls := 0
+	cached := parseTime(t, "2025-01-06 10:30:00")
+	s, err := New(1, Hour,
+		SetMaxRuns(3),
+		SetNextRun(&cached),
+		SetBeforeNextFunc(func(*Schedule) { hookCalls++ }),
+		SetAfterNextFunc(func(*time.Time) { hookCalls++ }),
+	)
+	require.NoError(t, err)
+
+	now := parseTime(t, "2025-01-06 10:00:00")
+	expected := []time.Time{
+		cached,
+		parseTime(t, "2025-01-06 11:30:00"),
+		parseTime(t, "2025-01-06 12:30:00"),
+	}
+
+	// The run limit stops the preview; repeated calls see the same runs
+	assert.Equal(t, expected, s.NextN(now, 10))
+	assert.Equal(t, expected, s.NextN(now, 10))
+
+	runs, err := s.Between(now, parseTime(t, "2025-01-06 12:00:00"))
+	require.NoError(t, err)
+	assert.Equal(t, expected[:2], runs)
+
+	assert.Equal(t, 0, hookCalls)
+	assert.Equal(t, 3, s.RemainingRuns())
+	assert.Equal(t, cached, s.nextRun)
+
+	require.NoError(t, s.Set(Disable()))
+	assert.Empty(t, s.NextN(now, 10))
+}
+
+func TestSchedule_Between(t *testing.T) {
+	s, err := New(15, Minute)
+	require.NoError(t, err)
+
+	runs, err := s.Between(parseTime(t, "2025-01-06 09:00:00"), parseTime(t, "2025-01-06 10:00:00"))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		parseTime(t, "2025-01-06 09:15:00"),
+		parseTime(t, "2025-01-06 09:30:00"),
+		parseTime(t, "2025-01-06 09:45:00"),
+		parseTime(t, "2025-01-06 10:00:00"),
+	}, runs)
+
+	endDate := parseTime(t, "2025-01-06 09:40:00")
+	require.NoError(t, s.Set(SetEndDate(&endDate)))
+	runs, err = s.Between(parseTime(t, "2025-01-06 09:00:00"), parseTime(t, "2025-01-06 10:00:00"))
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+}
+
+func TestSchedule_BetweenCap(t *testing.T) {
+	s, err := New(1, Second)
+	require.NoError(t, err)
+
+	from := parseTime(t, "2025-01-06 00:00:00")
+	runs, err := s.Between(from, from.AddDate(0, 0, 1))
+	assert.ErrorIs(t, err, ErrTooManyOccurrences)
+	require.Len(t, runs, maxPreviewOccurrences)
+	assert.Equal(t, from.Add(maxPreviewOccurrences*time.Second), runs[len(runs)-1])
+
+	// Exactly at the cap is fine
+	runs, err = s.Between(from, from.Add(maxPreviewOccurrences*time.Second))
+	require.NoError(t, err)
+	assert.Len(t, runs, maxPreviewOccurrences)
+}
@@ -0,0 +1,846 @@
+package robfigcronschedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRRulePeriods bounds how many FREQ periods are expanded when iterating an
+// RRULE without COUNT or UNTIL.
+const maxRRulePeriods = 100000
+
+// rruleFreq is the FREQ part of an RRULE.
+type rruleFreq int
+
+const (
+	freqSecondly rruleFreq = iota
+	freqMinutely
+	freqHourly
+	freqDaily
+	freqWeekly
+	freqMonthly
+	freqYearly
+)
+
+var rruleFreqNames = map[string]rruleFreq{
+	"SECONDLY": freqSecondly,
+	"MINUTELY": freqMinutely,
+	"HOURLY":   freqHourly,
+	"DAILY":    freqDaily,
+	"WEEKLY":   freqWeekly,
+	"MONTHLY":  freqMonthly,
+	"YEARLY":   freqYearly,
+}
+
+var rruleWeekdayNames = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// UnsupportedRRuleError reports an RRULE part that is valid RFC 5545 but cannot
+// be represented or expanded by this package.
+type UnsupportedRRuleError struct {
+	// Part is the RRULE part name, such as "BYSETPOS".
+	Part string
+	// Value is the part's value as written in the rule.
+	Value string
+}
+
+func (e *UnsupportedRRuleError) Error() string {
+	return fmt.Sprintf("unsupported RRULE part %s=%s", e.Part, e.Value)
+}
+
+// rruleDay is a BYDAY entry. ordinal is 0 for "every such weekday", 1..53 for
+// the nth occurrence and negative for the nth occurrence from the end.
+type rruleDay struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// rrule is a parsed RFC 5545 recurrence rule.
+type rrule struct {
+	freq       rruleFreq
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []rruleDay
+	byMonthDay []int
+	byMonth    []time.Month
+	byHour     []int
+	wkst       time.Weekday
+}
+
+// parseRRule parses the value of an RRULE property, such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE". A leading "RRULE:" is accepted.
+// UNTIL values without a zone are interpreted in loc.
+//
+// Returns ErrInvalidRRule for malformed rules and *UnsupportedRRuleError for
+// parts that are not supported.
+func parseRRule(value string, loc *time.Location) (*rrule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	r := &rrule{interval: 1, wkst: time.Monday}
+	hasFreq := false
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.freq, ok = rruleFreqNames[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("%w: FREQ=%s", ErrInvalidRRule, val)
+			}
+			hasFreq = true
+		case "INTERVAL":
+			r.interval, err = parsePositiveInt(val)
+		case "COUNT":
+			r.count, err = parsePositiveInt(val)
+		case "UNTIL":
+			r.until, _, err = parseICSTime(val, "", loc)
+		case "BYDAY":
+			r.byDay, err = parseRRuleDays(val)
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseIntList(val, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(val, 1, 12)
+			for _, m := range months {
+				r.byMonth = append(r.byMonth, time.Month(m))
+			}
+			sort.Slice(r.byMonth, func(i, j int) bool { return r.byMonth[i] < r.byMonth[j] })
+		case "BYHOUR":
+			r.byHour, err = parseIntList(val, 0, 23)
+			sort.Ints(r.byHour)
+		case "WKST":
+			r.wkst, ok = rruleWeekdayNames[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("%w: WKST=%s", ErrInvalidRRule, val)
+			}
+		default:
+			return nil, &UnsupportedRRuleError{Part: strings.ToUpper(name), Value: val}
+		}
+		if err != nil {
+			if _, unsupported := err.(*UnsupportedRRuleError); unsupported {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRRule, strings.ToUpper(name), err)
+		}
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("%w: missing FREQ", ErrInvalidRRule)
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRRule)
+	}
+	for _, d := range r.byDay {
+		if d.ordinal != 0 && r.freq != freqMonthly && r.freq != freqYearly {
+			return nil, &UnsupportedRRuleError{Part: "BYDAY", Value: formatRRuleDay(d)}
+		}
+		if d.ordinal != 0 && r.freq == freqYearly && len(r.byMonth) == 0 {
+			return nil, &UnsupportedRRuleError{Part: "BYDAY", Value: formatRRuleDay(d)}
+		}
+	}
+
+	return r, nil
+}
+
+// occurrences calls fn with the start of each occurrence of the rule, in
+// order, beginning at dtstart. Iteration stops when fn returns false, when
+// COUNT or UNTIL is reached, or after maxRRulePeriods FREQ periods.
+func (r *rrule) occurrences(dtstart time.Time, fn func(time.Time) bool) {
+	count := 0
+	for period := 0; period < maxRRulePeriods; period++ {
+		for _, occurrence := range r.expand(dtstart, period) {
+			if occurrence.Before(dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && occurrence.After(r.until) {
+				return
+			}
+			if r.count > 0 && count >= r.count {
+				return
+			}
+			count++
+			if !fn(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// expand returns the sorted candidate occurrences of the given FREQ period,
+// counting periods from the one containing dtstart.
+func (r *rrule) expand(dtstart time.Time, period int) []time.Time {
+	step := period * r.interval
+	var days []time.Time
+
+	switch r.freq {
+	case freqSecondly:
+		return r.filter(dtstart.Add(time.Duration(step) * time.Second))
+	case freqMinutely:
+		return r.filter(dtstart.Add(time.Duration(step) * time.Minute))
+	case freqHourly:
+		return r.filter(dtstart.Add(time.Duration(step) * time.Hour))
+	case freqDaily:
+		days = r.filter(dtstart.AddDate(0, 0, step))
+	case freqWeekly:
+		offset := (int(dtstart.Weekday()) - int(r.wkst) + 7) % 7
+		weekStart := dtstart.AddDate(0, 0, step*7-offset)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if len(r.byDay) == 0 && day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			days = append(days, r.filter(day)...)
+		}
+	case freqMonthly:
+		first := time.Date(
+			dtstart.Year(), dtstart.Month()+time.Month(step), 1,
+			dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(),
+			dtstart.Location(),
+		)
+		if len(r.byMonth) == 0 || containsMonth(r.byMonth, first.Month()) {
+			days = r.monthDays(first, dtstart.Day())
+		}
+	case freqYearly:
+		months := r.byMonth
+		switch {
+		case len(months) > 0:
+		case len(r.byMonthDay) > 0 || len(r.byDay) > 0:
+			// Without BYMONTH, BYMONTHDAY and BYDAY apply to the whole year
+			for month := time.January; month <= time.December; month++ {
+				months = append(months, month)
+			}
+		default:
+			months = []time.Month{dtstart.Month()}
+		}
+		for _, month := range months {
+			first := time.Date(
+				dtstart.Year()+step, month, 1,
+				dtstart.Hour(), dtstart.Minute(), dtstart.Second(), dtstart.Nanosecond(),
+				dtstart.Location(),
+			)
+			days = append(days, r.monthDays(first, dtstart.Day())...)
+		}
+	}
+
+	return r.expandHours(days)
+}
+
+// filter returns t if it passes the BYMONTH, BYMONTHDAY, BYDAY and (for
+// sub-daily frequencies) BYHOUR limits.
+func (r *rrule) filter(t time.Time) []time.Time {
+	if len(r.byMonth) > 0 && !containsMonth(r.byMonth, t.Month()) {
+		return nil
+	}
+	if len(r.byMonthDay) > 0 && !containsDay(r.byMonthDay, t) {
+		return nil
+	}
+	if len(r.byDay) > 0 {
+		found := false
+		for _, d := range r.byDay {
+			found = found || d.weekday == t.Weekday()
+		}
+		if !found {
+			return nil
+		}
+	}
+	if r.freq < freqDaily && len(r.byHour) > 0 && !containsInt(r.byHour, t.Hour()) {
+		return nil
+	}
+	return []time.Time{t}
+}
+
+// monthDays returns the days of the month starting at first that match
+// BYMONTHDAY and BYDAY. When both are set a day must match both. When neither
+// is set, the month's day-th day is used if the month has one.
+func (r *rrule) monthDays(first time.Time, day int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		if day > last {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, day-1)}
+	}
+
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		date := first.AddDate(0, 0, d-1)
+		if len(r.byMonthDay) > 0 && !containsDay(r.byMonthDay, date) {
+			continue
+		}
+		if len(r.byDay) > 0 && !matchesRRuleDay(r.byDay, date, last) {
+			continue
+		}
+		days = append(days, date)
+	}
+	return days
+}
+
+// expandHours repeats each day at every BYHOUR hour for daily and longer
+// frequencies. Other frequencies, or rules without BYHOUR, are returned as is.
+func (r *rrule) expandHours(days []time.Time) []time.Time {
+	if r.freq < freqDaily || len(r.byHour) == 0 {
+		return days
+	}
+
+	expanded := make([]time.Time, 0, len(days)*len(r.byHour))
+	for _, day := range days {
+		for _, hour := range r.byHour {
+			expanded = append(expanded, time.Date(
+				day.Year(), day.Month(), day.Day(),
+				hour, day.Minute(), day.Second(), day.Nanosecond(),
+				day.Location(),
+			))
+		}
+	}
+	return expanded
+}
+
+// matchesRRuleDay reports whether date matches one of the BYDAY entries within
+// its month of last days.
+func matchesRRuleDay(days []rruleDay, date time.Time, last int) bool {
+	for _, d := range days {
+		if d.weekday != date.Weekday() {
+			continue
+		}
+		switch {
+		case d.ordinal == 0:
+			return true
+		case d.ordinal > 0 && (date.Day()-1)/7+1 == d.ordinal:
+			return true
+		case d.ordinal < 0 && (last-date.Day())/7+1 == -d.ordinal:
+			return true
+		}
+	}
+	return false
+}
+
+// containsDay reports whether t's day of month matches one of the BYMONTHDAY
+// values, where negative values count from the end of the month.
+func containsDay(days []int, t time.Time) bool {
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, d := range days {
+		if d == t.Day() || (d < 0 && last+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRRuleDays parses a BYDAY list such as "MO,WE" or "2TU,-1FR".
+func parseRRuleDays(value string) ([]rruleDay, error) {
+	var days []rruleDay
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		weekday, ok := rruleWeekdayNames[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+
+		day := rruleDay{weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			ordinal, err := strconv.Atoi(prefix)
+			if err != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", item)
+			}
+			day.ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// formatRRuleDay formats a BYDAY entry, such as "MO" or "-1FR".
+func formatRRuleDay(d rruleDay) string {
+	for name, weekday := range rruleWeekdayNames {
+		if weekday != d.weekday {
+			continue
+		}
+		if d.ordinal == 0 {
+			return name
+		}
+		return strconv.Itoa(d.ordinal) + name
+	}
+	return ""
+}
+
+// parseIntList parses a comma separated list of non-zero integers within
+// [min, max].
+func parseIntList(value string, min, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || v < min || v > max || (v == 0 && min < 0) {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// parsePositiveInt parses an integer that must be at least 1.
+func parsePositiveInt(value string) (int, error) {
+	v, err := strconv.Atoi(value)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return v, nil
+}
+
+// rruleUnits maps interval time units to RRULE frequencies.
+var rruleUnits = map[IntervalTimeUnit]rruleFreq{
+	Second: freqSecondly,
+	Minute: freqMinutely,
+	Hour:   freqHourly,
+	Day:    freqDaily,
+	Week:   freqWeekly,
+	Month:  freqMonthly,
+	Year:   freqYearly,
+}
+
+// RRule converts the schedule to an RFC 5545 recurrence rule. The interval and
+// time unit become FREQ and INTERVAL, allowed weekdays become BYDAY, the time
+// window becomes BYHOUR, the end date becomes UNTIL and the run limit becomes
+// COUNT. If a start date is set, it is emitted as a DTSTART line before the
+// RRULE line, in the location set with SetLocation if there is one.
+//
+// Windows include their end, so BYHOUR=9,...,16 is a window from 09:00 to the
+// last instant before 17:00, or to 17:00 if no run falls on it. Daily and
+// longer rules repeat DTSTART's time of day instead, so these schedules may
+// only have a start time equal to the start date's time of day.
+//
+// Runtime state (enabled, precision, next run and hooks) is not part of the
+// rule. Returns ErrRRuleNotRepresentable if the schedule uses features an
+// RRULE cannot express, such as per-weekday windows, calendars, time
+// windows that do not start and end on the hour, a location other than UTC
+// without a start date to carry it, or a start date on a day some months
+// lack without MonthEndSkip.
+//
+// Example:
+//
+//	// Every 30 minutes on weekdays from 09:00 to 16:59:59.999999999
+//	rule, err := schedule.RRule()
+//	// DTSTART;TZID=Asia/Jakarta:20250106T000000
+//	// RRULE:FREQ=MINUTELY;INTERVAL=30;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9,10,11,12,13,14,15,16
+func (s *Schedule) RRule() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freq, ok := rruleUnits[s.intervalTimeUnit]
+	if s.intervalTimeUnit == BusinessDay {
+		return "", fmt.Errorf("%w: business days", ErrRRuleNotRepresentable)
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: unknown interval time unit", ErrRRuleNotRepresentable)
+	}
+	switch {
+	case s.weeklyWindows != nil:
+		return "", fmt.Errorf("%w: per-weekday time windows", ErrRRuleNotRepresentable)
+	case s.calendar != nil:
+		return "", fmt.Errorf("%w: calendar", ErrRRuleNotRepresentable)
+	case s.nthDay != 0:
+		return "", fmt.Errorf("%w: nth day of the month", ErrRRuleNotRepresentable)
+	case s.alignMode != AlignNone:
+		return "", fmt.Errorf("%w: alignment", ErrRRuleNotRepresentable)
+	case s.hasJitter():
+		return "", fmt.Errorf("%w: jitter", ErrRRuleNotRepresentable)
+	case s.maxRuns > 0 && s.endDate != nil:
+		return "", fmt.Errorf("%w: both run limit and end date", ErrRRuleNotRepresentable)
+	}
+
+	// The rule is evaluated in DTSTART's zone, which must be the pinned one
+	loc := time.UTC
+	if s.location != nil {
+		loc = s.location
+	} else if s.startDate != nil {
+		loc = s.startDate.Location()
+	}
+	var lines []string
+	switch {
+	case s.startDate != nil:
+		lines = append(lines, "DTSTART"+formatICSTime(s.startDate.In(loc)))
+	case loc != time.UTC:
+		return "", fmt.Errorf("%w: time zone without a start date", ErrRRuleNotRepresentable)
+	}
+
+	// Monthly and yearly rules skip months without DTSTART's day, like
+	// MonthEndSkip
+	if freq >= freqMonthly && s.monthEnd != MonthEndSkip && s.startDate != nil {
+		start := s.startDate.In(loc)
+		if start.Day() > 28 && (freq == freqMonthly || start.Month() == time.February) {
+			return "", fmt.Errorf("%w: month end policy other than skip", ErrRRuleNotRepresentable)
+		}
+	}
+
+	parts := []string{"FREQ=" + freqName(freq)}
+	if s.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(s.interval))
+	}
+
+	if s.allowedWeekdays != nil {
+		var days []string
+		for _, day := range []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+			time.Friday, time.Saturday, time.Sunday,
+		} {
+			if (*s.allowedWeekdays)[day] {
+				days = append(days, formatRRuleDay(rruleDay{weekday: day}))
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if freq >= freqDaily && !s.runsAtStartClock(loc) {
+		return "", fmt.Errorf("%w: time of day of a daily or longer interval",
+			ErrRRuleNotRepresentable)
+	}
+	if s.hasWindows() && freq < freqDaily {
+		hours, err := s.windowHours(loc)
+		if err != nil {
+			return "", err
+		}
+		if len(hours) < 24 {
+			parts = append(parts, "BYHOUR="+joinInts(hours))
+		}
+	}
+
+	if s.endDate != nil {
+		parts = append(parts, "UNTIL="+s.endDate.UTC().Format(icsDateTimeLayout)+"Z")
+	}
+	if s.maxRuns > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(s.maxRuns))
+	}
+
+	lines = append(lines, "RRULE:"+strings.Join(parts, ";"))
+	return strings.Join(lines, "\n"), nil
+}
+
+// ParseRRule builds a Schedule from an RFC 5545 recurrence rule, optionally
+// preceded by a DTSTART line as produced by Schedule.RRule. Additional options
+// are applied after the ones derived from the rule.
+//
+// FREQ and INTERVAL map to the interval and time unit, BYDAY to allowed
+// weekdays, BYHOUR to time windows ending at the last instant of their last
+// hour (in DTSTART's zone, UTC otherwise), DTSTART to the start date, UNTIL to
+// the end date and COUNT to the run limit. A DTSTART with a TZID pins the
+// schedule to that zone. Daily and longer rules also run at DTSTART's time of
+// day, which becomes the start time, and monthly and yearly rules use
+// MonthEndSkip. FREQ=WEEKLY with BYDAY
+// and no INTERVAL maps to a daily schedule on those weekdays.
+//
+// Returns ErrInvalidRRule for malformed rules and *UnsupportedRRuleError for
+// parts a Schedule cannot represent, such as BYMONTHDAY, BYSETPOS, BYHOUR
+// with daily or longer rules, or BYDAY with monthly and yearly rules.
+//
+// Example:
+//
+//	schedule, err := ParseRRule("RRULE:FREQ=HOURLY;INTERVAL=2;BYDAY=MO,WE,FR;BYHOUR=8,9,10,11")
+func ParseRRule(value string, opts ...ScheduleOption) (*Schedule, error) {
+	loc := time.UTC
+	var rule string
+	var dtstart *time.Time
+	var zoned bool
+
+	for _, line := range strings.Split(strings.TrimSpace(value), "\n") {
+		line = strings.TrimSpace(line)
+		name, params, val, err := parseICSProperty(line)
+		if err != nil || name == "RRULE" {
+			rule = line
+			continue
+		}
+		if name != "DTSTART" {
+			return nil, &UnsupportedRRuleError{Part: name, Value: val}
+		}
+
+		prop := &icsProperty{params: params, value: val}
+		start, _, err := prop.time()
+		if err != nil {
+			return nil, fmt.Errorf("%w: DTSTART: %v", ErrInvalidRRule, err)
+		}
+		dtstart, loc = &start, start.Location()
+		zoned = params["TZID"] != ""
+	}
+
+	r, err := parseRRule(rule, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := Second
+	for u, freq := range rruleUnits {
+		if freq == r.freq {
+			unit = u
+		}
+	}
+
+	var options []ScheduleOption
+	switch {
+	case len(r.byMonthDay) > 0:
+		return nil, &UnsupportedRRuleError{Part: "BYMONTHDAY", Value: joinInts(r.byMonthDay)}
+	case len(r.byMonth) > 0:
+		var months []int
+		for _, m := range r.byMonth {
+			months = append(months, int(m))
+		}
+		return nil, &UnsupportedRRuleError{Part: "BYMONTH", Value: joinInts(months)}
+	}
+
+	if len(r.byDay) > 0 {
+		var weekdays []time.Weekday
+		var names []string
+		for _, d := range r.byDay {
+			names = append(names, formatRRuleDay(d))
+			if d.ordinal != 0 {
+				return nil, &UnsupportedRRuleError{Part: "BYDAY", Value: strings.Join(names, ",")}
+			}
+			weekdays = append(weekdays, d.weekday)
+		}
+		// The rule expands a month or year to every such weekday and skips
+		// periods starting on other weekdays, where a schedule moves on to
+		// the next allowed day
+		if r.freq >= freqMonthly || (r.freq >= freqDaily && r.interval > 1) {
+			return nil, &UnsupportedRRuleError{Part: "BYDAY", Value: strings.Join(names, ",")}
+		}
+		if unit == Week {
+			unit = Day
+		}
+		options = append(options, SetAllowedWeekdays(weekdays...))
+	}
+
+	if len(r.byHour) > 0 {
+		// The rule repeats each day at every hour, where a time window opens
+		// once a day
+		if r.freq >= freqDaily {
+			return nil, &UnsupportedRRuleError{Part: "BYHOUR", Value: joinInts(r.byHour)}
+		}
+		windows := hourWindows(r.byHour, loc)
+		switch len(windows) {
+		case 0:
+		case 1:
+			start := windows[0].Start
+			options = append(options, SetStartTime(&start))
+			if !windows[0].End.IsZero() {
+				end := windows[0].End
+				options = append(options, SetEndTime(&end))
+			}
+		default:
+			options = append(options, SetTimeWindows(windows...))
+		}
+	}
+
+	if r.freq >= freqMonthly {
+		// Monthly and yearly rules skip months without DTSTART's day
+		options = append(options, SetMonthEndPolicy(MonthEndSkip))
+	}
+	if zoned {
+		// The rule's weekdays and hours are those of DTSTART's zone
+		options = append(options, SetLocation(loc))
+	}
+	if dtstart != nil {
+		options = append(options, SetStartDate(dtstart))
+		// Daily and longer rules repeat DTSTART's time of day, where a
+		// schedule runs at midnight after its start date
+		if start := *dtstart; r.freq >= freqDaily && !start.Equal(startOfDay(start)) {
+			options = append(options, SetStartTime(&start))
+		}
+	}
+	if !r.until.IsZero() {
+		until := r.until
+		options = append(options, SetEndDate(&until))
+	}
+	if r.count > 0 {
+		options = append(options, SetMaxRuns(r.count))
+	}
+
+	return New(r.interval, unit, append(options, opts...)...)
+}
+
+// windowHours returns the sorted hours, in loc, covered by the schedule's
+// daily time windows. Each window must start on the hour and end on the hour
+// or at the last instant before it. Windows include their end, so a window
+// ending on the hour must not end on a run, which BYHOUR would leave out.
+func (s *Schedule) windowHours(loc *time.Location) ([]int, error) {
+	windows := s.timeWindows
+	if s.startTime != nil {
+		w := TimeWindow{Start: *s.startTime}
+		if s.endTime != nil {
+			w.End = *s.endTime
+		}
+		windows = []TimeWindow{w}
+	}
+
+	covered := make([]bool, 24)
+	for _, w := range windows {
+		start := w.Start.In(loc)
+		if !onTheHour(start) {
+			return nil, fmt.Errorf("%w: time window not starting on the hour", ErrRRuleNotRepresentable)
+		}
+
+		endHour := 24
+		if !w.End.IsZero() {
+			end := w.End.In(loc)
+			switch {
+			case onTheHour(end.Add(time.Nanosecond)):
+				// The last instant of an hour, as imported from BYHOUR
+				end = end.Add(time.Nanosecond)
+			case !onTheHour(end):
+				return nil, fmt.Errorf("%w: time window not ending on the hour", ErrRRuleNotRepresentable)
+			case s.endsOnRun(start, end):
+				return nil, fmt.Errorf("%w: time window ending on a run", ErrRRuleNotRepresentable)
+			}
+			endHour = end.Hour()
+		}
+
+		for hour := start.Hour(); hour != endHour; {
+			covered[hour] = true
+			if hour++; hour == 24 && endHour != 24 {
+				hour = 0
+			}
+		}
+	}
+
+	var hours []int
+	for hour, ok := range covered {
+		if ok {
+			hours = append(hours, hour)
+		}
+	}
+	return hours, nil
+}
+
+// onTheHour reports whether t is at the start of an hour.
+func onTheHour(t time.Time) bool {
+	return t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// endsOnRun reports whether a window from start to end, as wall clocks, runs
+// at its end: whether the interval divides the window's length.
+func (s *Schedule) endsOnRun(start, end time.Time) bool {
+	length := clockSeconds(end) - clockSeconds(start)
+	if length <= 0 {
+		length += 24 * 3600
+	}
+	return time.Duration(length)*time.Second%s.fixedInterval() == 0
+}
+
+// runsAtStartClock reports whether the schedule runs at the time of day of
+// its start date in loc, which DTSTART repeats for daily and longer rules:
+// midnight without time windows, or a start time alone at that time of day.
+func (s *Schedule) runsAtStartClock(loc *time.Location) bool {
+	if s.startDate == nil {
+		return !s.hasWindows()
+	}
+	startDate := s.startDate.In(loc)
+	if !s.hasWindows() {
+		return startDate.Equal(startOfDay(startDate))
+	}
+	if s.endTime != nil || len(s.timeWindows) > 0 {
+		return false
+	}
+	start := s.startTime.In(loc)
+	return clockSeconds(start) == clockSeconds(startDate) &&
+		start.Nanosecond() == startDate.Nanosecond()
+}
+
+// hourWindows groups sorted BYHOUR hours into time windows in loc. Consecutive
+// hours form one window ending at the last instant of its last hour; a run
+// ending at 23 joins a run starting at 0 as an overnight window. Returns nil
+// when all 24 hours are covered.
+func hourWindows(hours []int, loc *time.Location) []TimeWindow {
+	covered := make([]bool, 24)
+	for _, hour := range hours {
+		covered[hour] = true
+	}
+	if len(hours) == 0 || len(hours) == 24 {
+		return nil
+	}
+
+	var windows []TimeWindow
+	for hour := 0; hour < 24; hour++ {
+		// Only the first hour of each run opens a window
+		if !covered[hour] || covered[(hour+23)%24] {
+			continue
+		}
+
+		end := hour
+		for covered[(end+1)%24] {
+			end = (end + 1) % 24
+		}
+
+		// Windows include their end, so they end just before the next hour
+		w := TimeWindow{Start: time.Date(2000, 1, 1, hour, 0, 0, 0, loc)}
+		if end != 23 {
+			w.End = time.Date(2000, 1, 1, end, 59, 59, 999999999, loc)
+		}
+		windows = append(windows, w)
+	}
+	return windows
+}
+
+// freqName returns the RRULE name of a frequency.
+func freqName(freq rruleFreq) string {
+	for name, f := range rruleFreqNames {
+		if f == freq {
+			return name
+		}
+	}
+	return ""
+}
+
+// formatICSTime formats t as the parameters and value of a DATE-TIME property,
+// such as ":20250106T090000Z" or ";TZID=Asia/Jakarta:20250106T090000".
+func formatICSTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return ":" + t.Format(icsDateTimeLayout) + "Z"
+	}
+	return ";TZID=" + t.Location().String() + ":" + t.Format(icsDateTimeLayout)
+}
+
+// joinInts joins integers with commas.
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,524 @@
+package robfigcronschedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRRule_Expansion(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		dtstart  string
+		limit    int
+		expected []string
+	}{
+		{
+			name:    "daily with interval and count",
+			rule:    "FREQ=DAILY;INTERVAL=2;COUNT=3",
+			dtstart: "2024-03-11 09:00:00",
+			limit:   10,
+			expected: []string{
+				"2024-03-11 09:00:00",
+				"2024-03-13 09:00:00",
+				"2024-03-15 09:00:00",
+			},
+		},
+		{
+			name:    "weekly by day until",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,FR;UNTIL=20240322T090000Z",
+			dtstart: "2024-03-11 09:00:00",
+			limit:   10,
+			expected: []string{
+				"2024-03-11 09:00:00",
+				"2024-03-15 09:00:00",
+				"2024-03-18 09:00:00",
+				"2024-03-22 09:00:00",
+			},
+		},
+		{
+			name:    "monthly second tuesday",
+			rule:    "RRULE:FREQ=MONTHLY;BYDAY=2TU",
+			dtstart: "2024-01-09 10:00:00",
+			limit:   3,
+			expected: []string{
+				"2024-01-09 10:00:00",
+				"2024-02-13 10:00:00",
+				"2024-03-12 10:00:00",
+			},
+		},
+		{
+			name:    "monthly last friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart: "2024-01-26 10:00:00",
+			limit:   3,
+			expected: []string{
+				"2024-01-26 10:00:00",
+				"2024-02-23 10:00:00",
+				"2024-03-29 10:00:00",
+			},
+		},
+		{
+			name:    "monthly on the 31st skips short months",
+			rule:    "FREQ=MONTHLY",
+			dtstart: "2024-01-31 00:00:00",
+			limit:   3,
+			expected: []string{
+				"2024-01-31 00:00:00",
+				"2024-03-31 00:00:00",
+				"2024-05-31 00:00:00",
+			},
+		},
+		{
+			name:    "monthly last day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			dtstart: "2024-01-31 00:00:00",
+			limit:   3,
+			expected: []string{
+				"2024-01-31 00:00:00",
+				"2024-02-29 00:00:00",
+				"2024-03-31 00:00:00",
+			},
+		},
+		{
+			name:    "yearly by month",
+			rule:    "FREQ=YEARLY;BYMONTH=1,7",
+			dtstart: "2024-01-01 00:00:00",
+			limit:   3,
+			expected: []string{
+				"2024-01-01 00:00:00",
+				"2024-07-01 00:00:00",
+				"2025-01-01 00:00:00",
+			},
+		},
+		{
+			name:    "hourly limited by hour",
+			rule:    "FREQ=HOURLY;INTERVAL=4;BYHOUR=8,12,16",
+			dtstart: "2024-03-11 08:00:00",
+			limit:   4,
+			expected: []string{
+				"2024-03-11 08:00:00",
+				"2024-03-11 12:00:00",
+				"2024-03-11 16:00:00",
+				"2024-03-12 08:00:00",
+			},
+		},
+		{
+			name:    "daily expanded by hour",
+			rule:    "FREQ=DAILY;BYHOUR=9,17",
+			dtstart: "2024-03-11 09:30:00",
+			limit:   3,
+			expected: []string{
+				"2024-03-11 09:30:00",
+				"2024-03-11 17:30:00",
+				"2024-03-12 09:30:00",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRRule(tt.rule, time.UTC)
+			require.NoError(t, err)
+
+			var got []string
+			rule.occurrences(parseTime(t, tt.dtstart), func(occurrence time.Time) bool {
+				got = append(got, occurrence.Format("2006-01-02 15:04:05"))
+				return len(got) < tt.limit
+			})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseRRule_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		unsupported string
+	}{
+		{name: "missing freq", rule: "INTERVAL=2"},
+		{name: "invalid freq", rule: "FREQ=FORTNIGHTLY"},
+		{name: "invalid interval", rule: "FREQ=DAILY;INTERVAL=0"},
+		{name: "invalid weekday", rule: "FREQ=WEEKLY;BYDAY=XX"},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20240101"},
+		{name: "malformed part", rule: "FREQ=DAILY;COUNT"},
+		{name: "bysetpos", rule: "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=-1", unsupported: "BYSETPOS"},
+		{name: "byminute", rule: "FREQ=DAILY;BYMINUTE=30", unsupported: "BYMINUTE"},
+		{name: "weekly ordinal", rule: "FREQ=WEEKLY;BYDAY=2MO", unsupported: "BYDAY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseRRule(tt.rule, time.UTC)
+			require.Error(t, err)
+
+			if tt.unsupported == "" {
+				assert.ErrorIs(t, err, ErrInvalidRRule)
+				return
+			}
+			var unsupported *UnsupportedRRuleError
+			require.ErrorAs(t, err, &unsupported)
+			assert.Equal(t, tt.unsupported, unsupported.Part)
+		})
+	}
+}
+
+func TestSchedule_RRule(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	startDate := time.Date(2025, 1, 6, 0, 0, 0, 0, jakarta)
+	endDate := time.Date(2025, 6, 30, 17, 0, 0, 0, time.UTC)
+	halfPastNine := time.Date(2025, 1, 6, 9, 30, 0, 0, jakarta)
+	monthEnd := time.Date(2025, 1, 31, 0, 0, 0, 0, jakarta)
+	nine := time.Date(0, 1, 1, 9, 0, 0, 0, jakarta)
+	// Windows include their end, so BYHOUR hours end just before the next one
+	beforeNoon := time.Date(0, 1, 1, 11, 59, 59, 999999999, jakarta)
+	tenPM, beforeTwoAM := clock(22, 0), clock(2, 0).Add(-time.Nanosecond)
+
+	tests := []struct {
+		name     string
+		interval int
+		unit     IntervalTimeUnit
+		opts     []ScheduleOption
+		expected string
+	}{
+		{
+			name:     "plain daily",
+			interval: 1,
+			unit:     Day,
+			expected: "RRULE:FREQ=DAILY",
+		},
+		{
+			name:     "working hours",
+			interval: 30,
+			unit:     Minute,
+			opts: []ScheduleOption{
+				SetStartDate(&startDate),
+				SetStartTime(&nine),
+				SetEndTime(&beforeNoon),
+				SetAllowedWeekdays(time.Friday, time.Monday),
+				SetEndDate(&endDate),
+			},
+			expected: "DTSTART;TZID=Asia/Jakarta:20250106T000000\n" +
+				"RRULE:FREQ=MINUTELY;INTERVAL=30;BYDAY=MO,FR;BYHOUR=9,10,11;UNTIL=20250630T170000Z",
+		},
+		{
+			name:     "overnight window with count",
+			interval: 2,
+			unit:     Hour,
+			opts: []ScheduleOption{
+				SetStartTime(&tenPM),
+				SetEndTime(&beforeTwoAM),
+				SetMaxRuns(5),
+			},
+			expected: "RRULE:FREQ=HOURLY;INTERVAL=2;BYHOUR=0,1,22,23;COUNT=5",
+		},
+		{
+			name:     "multiple windows",
+			interval: 1,
+			unit:     Hour,
+			opts: []ScheduleOption{
+				SetTimeWindows(
+					TimeWindow{Start: clock(8, 0), End: clock(10, 0).Add(-time.Nanosecond)},
+					TimeWindow{Start: clock(21, 0)},
+				),
+			},
+			expected: "RRULE:FREQ=HOURLY;BYHOUR=8,9,21,22,23",
+		},
+		{
+			name:     "daily at the start date's time of day",
+			interval: 1,
+			unit:     Day,
+			opts: []ScheduleOption{
+				SetStartDate(&halfPastNine),
+				SetStartTime(&halfPastNine),
+				SetAllowedWeekdays(time.Monday, time.Friday),
+			},
+			expected: "DTSTART;TZID=Asia/Jakarta:20250106T093000\n" +
+				"RRULE:FREQ=DAILY;BYDAY=MO,FR",
+		},
+		{
+			name:     "monthly from the 31st skipping short months",
+			interval: 1,
+			unit:     Month,
+			opts:     []ScheduleOption{SetStartDate(&monthEnd), SetMonthEndPolicy(MonthEndSkip)},
+			expected: "DTSTART;TZID=Asia/Jakarta:20250131T000000\nRRULE:FREQ=MONTHLY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.interval, tt.unit, tt.opts...)
+			require.NoError(t, err)
+
+			rule, err := s.RRule()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rule)
+
+			// The imported rule runs like the schedule
+			imported, err := ParseRRule(rule)
+			require.NoError(t, err)
+			from := time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta)
+			assert.Equal(t, inUTC(s.NextN(from, 50)), inUTC(imported.NextN(from, 50)))
+		})
+	}
+}
+
+func TestSchedule_RRulePinned(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	// Monday 03:00-06:00 in Jakarta is Sunday 20:00-23:00 in UTC
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	three := time.Date(0, 1, 1, 3, 0, 0, 0, jakarta)
+	beforeSix := time.Date(0, 1, 1, 5, 59, 59, 999999999, jakarta)
+	s, err := New(1, Hour,
+		SetLocation(jakarta),
+		SetStartDate(&startDate),
+		SetStartTime(&three),
+		SetEndTime(&beforeSix),
+		SetAllowedWeekdays(time.Monday),
+	)
+	require.NoError(t, err)
+
+	rule, err := s.RRule()
+	require.NoError(t, err)
+	assert.Equal(t, "DTSTART;TZID=Asia/Jakarta:20250101T070000\n"+
+		"RRULE:FREQ=HOURLY;BYDAY=MO;BYHOUR=3,4,5", rule)
+
+	imported, err := ParseRRule(rule)
+	require.NoError(t, err)
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := s.NextN(from, 6)
+	assert.Equal(t, time.Date(2025, 1, 5, 20, 0, 0, 0, time.UTC), runs[0])
+	assert.Equal(t, runs, imported.NextN(from, 6))
+
+	// Without a start date, no DTSTART carries the zone
+	s, err = New(1, Hour, SetLocation(jakarta), SetAllowedWeekdays(time.Monday))
+	require.NoError(t, err)
+	_, err = s.RRule()
+	assert.ErrorIs(t, err, ErrRRuleNotRepresentable)
+}
+
+// inUTC returns times in UTC, so times from different zones compare equal.
+func inUTC(times []time.Time) []time.Time {
+	for i, t := range times {
+		times[i] = t.UTC()
+	}
+	return times
+}
+
+func TestSchedule_RRuleNotRepresentable(t *testing.T) {
+	endDate := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	startDate := time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)
+	halfPastNine := clock(9, 30)
+	monthEnd := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	leapDay := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		unit IntervalTimeUnit
+		opts []ScheduleOption
+	}{
+		{
+			name: "window not on the hour",
+			opts: []ScheduleOption{SetStartTime(&halfPastNine)},
+		},
+		{
+			name: "window ending on a run",
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: clock(9, 0), End: clock(17, 0)}),
+			},
+		},
+		{
+			name: "window on a daily interval",
+			unit: Day,
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: clock(9, 0), End: clock(17, 0)}),
+			},
+		},
+		{
+			name: "start time without a start date",
+			unit: Week,
+			opts: []ScheduleOption{SetStartTime(&halfPastNine)},
+		},
+		{
+			name: "start date after midnight on a daily interval",
+			unit: Day,
+			opts: []ScheduleOption{SetStartDate(&startDate)},
+		},
+		{
+			name: "weekly windows",
+			opts: []ScheduleOption{SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+				time.Monday: {{Start: clock(9, 0), End: clock(17, 0)}},
+			})},
+		},
+		{
+			name: "calendar",
+			opts: []ScheduleOption{SetCalendar(NewDateSet(endDate))},
+		},
+		{
+			name: "alignment",
+			opts: []ScheduleOption{AlignToClock(0)},
+		},
+		{
+			name: "jitter",
+			opts: []ScheduleOption{SetJitter(time.Minute)},
+		},
+		{
+			name: "monthly from the 31st without skipping",
+			unit: Month,
+			opts: []ScheduleOption{SetStartDate(&monthEnd)},
+		},
+		{
+			name: "yearly from February 29 without skipping",
+			unit: Year,
+			opts: []ScheduleOption{SetStartDate(&leapDay), SetMonthEndPolicy(MonthEndClamp)},
+		},
+		{
+			name: "run limit and end date",
+			opts: []ScheduleOption{SetMaxRuns(3), SetEndDate(&endDate)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit := Hour
+			if tt.unit != Second {
+				unit = tt.unit
+			}
+			s, err := New(1, unit, tt.opts...)
+			require.NoError(t, err)
+
+			_, err = s.RRule()
+			assert.ErrorIs(t, err, ErrRRuleNotRepresentable)
+		})
+	}
+}
+
+func TestParseRRule_Schedule(t *testing.T) {
+	s, err := ParseRRule("DTSTART;TZID=Asia/Jakarta:20250106T000000\n" +
+		"RRULE:FREQ=MINUTELY;INTERVAL=30;BYDAY=MO,FR;BYHOUR=9,10,11;UNTIL=20250630T170000Z")
+	require.NoError(t, err)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	assert.Equal(t, 30, s.interval)
+	assert.Equal(t, Minute, s.intervalTimeUnit)
+	require.NotNil(t, s.startDate)
+	assert.True(t, s.startDate.Equal(time.Date(2025, 1, 6, 0, 0, 0, 0, jakarta)))
+	require.NotNil(t, s.endDate)
+	assert.True(t, s.endDate.Equal(time.Date(2025, 6, 30, 17, 0, 0, 0, time.UTC)))
+
+	// Friday's window closes before noon, so the run after 11:50 is Monday 09:00
+	friday := time.Date(2025, 1, 10, 11, 15, 0, 0, jakarta)
+	assert.Equal(t, time.Date(2025, 1, 10, 11, 45, 0, 0, jakarta), s.Next(friday).In(jakarta))
+	s.nextRun = time.Time{}
+	assert.Equal(t,
+		time.Date(2025, 1, 13, 9, 0, 0, 0, jakarta),
+		s.Next(time.Date(2025, 1, 10, 11, 50, 0, 0, jakarta)).In(jakarta),
+	)
+}
+
+func TestParseRRule_RoundTrip(t *testing.T) {
+	rules := []string{
+		"RRULE:FREQ=DAILY",
+		"RRULE:FREQ=HOURLY;INTERVAL=2;BYHOUR=0,1,22,23;COUNT=5",
+		"RRULE:FREQ=HOURLY;BYHOUR=8,9,21,22,23",
+		"RRULE:FREQ=MINUTELY;INTERVAL=15;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9,10,11,12,13,14,15,16",
+		"DTSTART:20250106T090000Z\nRRULE:FREQ=MONTHLY;INTERVAL=3;UNTIL=20261231T000000Z",
+	}
+
+	for _, rule := range rules {
+		t.Run(rule, func(t *testing.T) {
+			s, err := ParseRRule(rule)
+			require.NoError(t, err)
+
+			got, err := s.RRule()
+			require.NoError(t, err)
+			assert.Equal(t, rule, got)
+		})
+	}
+}
+
+func TestParseRRule_WeeklyByDay(t *testing.T) {
+	s, err := ParseRRule("FREQ=WEEKLY;BYDAY=TU,TH")
+	require.NoError(t, err)
+
+	assert.Equal(t, Day, s.intervalTimeUnit)
+	assert.Equal(t, 1, s.interval)
+
+	rule, err := s.RRule()
+	require.NoError(t, err)
+	assert.Equal(t, "RRULE:FREQ=DAILY;BYDAY=TU,TH", rule)
+}
+
+func TestParseRRule_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		part string
+	}{
+		{name: "by month day", rule: "FREQ=MONTHLY;BYMONTHDAY=15", part: "BYMONTHDAY"},
+		{name: "by month", rule: "FREQ=YEARLY;BYMONTH=1,7", part: "BYMONTH"},
+		{name: "ordinal weekday", rule: "FREQ=MONTHLY;BYDAY=-1FR", part: "BYDAY"},
+		{name: "biweekly by day", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", part: "BYDAY"},
+		{name: "every other day by day", rule: "FREQ=DAILY;INTERVAL=2;BYDAY=MO", part: "BYDAY"},
+		{name: "monthly by day", rule: "FREQ=MONTHLY;BYDAY=MO", part: "BYDAY"},
+		{name: "daily by hour", rule: "FREQ=DAILY;BYHOUR=9,17", part: "BYHOUR"},
+		{name: "by set position", rule: "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1", part: "BYSETPOS"},
+		{name: "extra property", rule: "EXDATE:20250101\nRRULE:FREQ=DAILY", part: "EXDATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRRule(tt.rule)
+
+			var unsupported *UnsupportedRRuleError
+			require.ErrorAs(t, err, &unsupported)
+			assert.Equal(t, tt.part, unsupported.Part)
+		})
+	}
+
+	_, err := ParseRRule("FREQ=DAILY;INTERVAL=x")
+	assert.ErrorIs(t, err, ErrInvalidRRule)
+}
+
+func TestParseRRule_RunsLikeExpansion(t *testing.T) {
+	rules := []string{
+		"DTSTART:20250106T093000Z\nRRULE:FREQ=DAILY",
+		"DTSTART:20250106T000000Z\nRRULE:FREQ=MINUTELY;INTERVAL=30;BYHOUR=9,10,11,12,13,14,15,16",
+		"DTSTART:20250106T120000Z\nRRULE:FREQ=HOURLY;INTERVAL=2;BYHOUR=0,1,22,23",
+		"DTSTART;TZID=Asia/Jakarta:20250106T083000\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		"DTSTART:20250115T083000Z\nRRULE:FREQ=MONTHLY;COUNT=4",
+		"DTSTART:20250131T083000Z\nRRULE:FREQ=MONTHLY;COUNT=4",
+		"DTSTART:20240229T000000Z\nRRULE:FREQ=YEARLY;COUNT=2",
+	}
+
+	for _, rule := range rules {
+		t.Run(rule, func(t *testing.T) {
+			s, err := ParseRRule(rule)
+			require.NoError(t, err)
+			require.NotNil(t, s.startDate)
+			dtstart := *s.startDate
+
+			r, err := parseRRule(rule[strings.Index(rule, "RRULE:"):], dtstart.Location())
+			require.NoError(t, err)
+			var expected []time.Time
+			r.occurrences(dtstart, func(occurrence time.Time) bool {
+				expected = append(expected, occurrence)
+				return len(expected) < 30
+			})
+
+			runs := runsLikeCron(s, dtstart.Add(-time.Nanosecond), 30)
+			assert.Equal(t, inUTC(expected), inUTC(runs))
+		})
+	}
+}
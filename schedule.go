@@ -5,13 +5,17 @@ package robfigcronschedule
 
 import (
 	"log"
+	"math/rand"
+	"sync"
 	"time"
 )
 
 // Schedule implements the robfig/cron.Schedule interface with enhanced features.
 // It supports:
 //   - Start dates (when the schedule becomes active)
+//   - End dates (when the schedule expires)
 //   - Daily time windows (startTime/endTime constraints)
+//   - Blackout dates through a pluggable Calendar
 //   - Flexible intervals with various time units
 //   - Precision vs non-precision modes
 //   - Before/after execution hooks
@@ -26,15 +30,36 @@ import (
 //	    SetEndTime(time.Date(0, 0, 0, 17, 0, 0, 0, time.UTC)),
 //	    EnablePrecision(),
 //	)
+//
+// A Schedule is safe for concurrent use, so Set may be called while
+// robfig/cron calls Next from its own goroutine. A Schedule must not be copied
+// after first use.
 type Schedule struct {
+	// mu guards all fields below. Hooks are called without holding it, so
+	// they may call Set.
+	mu sync.Mutex
+
 	// startDate controls when the schedule becomes active (optional)
 	startDate *time.Time
 
+	// endDate controls when the schedule expires (optional)
+	// After endDate, Next returns the zero time.
+	endDate *time.Time
+
 	// startTime/endTime define daily time window constraints (optional)
 	// If only startTime is set, endTime defaults to 23:59:59
 	startTime *time.Time
 	endTime   *time.Time
 
+	// timeWindows defines multiple daily time windows (optional)
+	// Cannot be combined with startTime/endTime.
+	timeWindows []TimeWindow
+
+	// weeklyWindows defines time windows per weekday (optional)
+	// Days without an entry are skipped. Cannot be combined with
+	// startTime/endTime or timeWindows.
+	weeklyWindows map[time.Weekday][]TimeWindow
+
 	// allowedWeekdays restricts execution to specific days of the week (optional)
 	// If nil, the schedule can run on any day of the week.
 	// If set, the schedule will only execute on the specified weekdays.
@@ -43,9 +68,53 @@ type Schedule struct {
 	// the intended interval timing and triggering validation errors.
 	allowedWeekdays *map[time.Weekday]bool
 
+	// alignMode aligns runs of fixed-length units to the clock or to
+	// alignAnchor, shifted by alignOffset. See AlignToClock and AlignToAnchor.
+	alignMode   AlignMode
+	alignAnchor time.Time
+	alignOffset time.Duration
+
+	// nthDay restricts Month intervals to the nth allowed day of the month,
+	// counted from the end when negative. 0 means no restriction.
+	nthDay int
+
+	// monthEnd controls Month and Year steps to a month that lacks the
+	// anchor day: the start date's day of month, or monthDay without one.
+	monthEnd MonthEndPolicy
+
+	// monthDay is the anchor day without a start date: the day of month of
+	// the time Next first computes a run from. 0 until then.
+	monthDay int
+
+	// dstGap and dstOverlap resolve window opening times that DST transitions
+	// skip or repeat.
+	dstGap     DSTGapPolicy
+	dstOverlap DSTOverlapPolicy
+
+	// location pins evaluation to a time zone (optional)
+	// If nil, Next evaluates windows, dates and weekdays in t's location.
+	location *time.Location
+
+	// calendar blocks specific dates such as holidays (optional)
+	// Blocked dates are skipped like disallowed weekdays.
+	calendar Calendar
+
 	// enabled controls whether the schedule is active
 	enabled bool
 
+	// pausedUntil re-enables a disabled schedule once Next reaches it (optional)
+	pausedUntil *time.Time
+
+	// disabledRecheck is how far ahead Next schedules the next check while
+	// the schedule is disabled. 0 means defaultDisabledRecheck.
+	disabledRecheck time.Duration
+
+	// disabledMode selects between rechecking and sleeping while disabled.
+	// asleep is set once Next returned the zero time because the schedule
+	// is disabled, until the wake hook is called.
+	disabledMode DisabledMode
+	asleep       bool
+
 	// interval and intervalTimeUnit control scheduling frequency
 	// interval must be >= 1
 	interval         int
@@ -54,6 +123,13 @@ type Schedule struct {
 	// nextRun caches the next calculated run time for efficiency
 	nextRun time.Time
 
+	// maxRuns limits how many distinct run times Next may produce (optional)
+	// 0 means unlimited. remainingRuns counts down from maxRuns and lastRun
+	// remembers the last counted run so repeated results are counted once.
+	maxRuns       int
+	remainingRuns int
+	lastRun       time.Time
+
 	// precision controls scheduling behavior:
 	// - true: strict interval adherence within time windows
 	// - false: round up from startTime using intervals
@@ -62,6 +138,27 @@ type Schedule struct {
 	// Hook functions called before/after Next() calculations
 	beforeNext func(*Schedule)
 	afterNext  func(next *time.Time)
+
+	// stateChange is called when the schedule is disabled or resumes
+	stateChange func(enabled bool)
+
+	// wake is called when Set gives a sleeping schedule runs again
+	wake func()
+
+	// jitter is the largest random delay of a run and splay the fixed delay
+	// of this instance, below splayMax, derived from splayKey. jitterBase and
+	// jitterRun remember the last run before and after its delay. jitterRand
+	// is the random source, nil for math/rand.
+	jitter     time.Duration
+	splayKey   string
+	splayMax   time.Duration
+	splay      time.Duration
+	jitterBase time.Time
+	jitterRun  time.Time
+	jitterRand *rand.Rand
+
+	// trace records the steps of Next while Explain runs, nil otherwise
+	trace *tracer
 }
 
 // New creates a new Schedule with the given options.
@@ -94,6 +191,9 @@ func New(
 
 // Set updates the schedule with new options, validating the result.
 // If validation fails, the schedule is rolled back to its previous state.
+// If the options disable or re-enable the schedule, the state change hook is
+// called once the schedule is updated. If they give a schedule sleeping in
+// DisabledSleep mode runs again, the wake hook is called after it.
 //
 // Example:
 //
@@ -102,12 +202,50 @@ func New(
 //	    // Schedule unchanged, handle error
 //	}
 func (s *Schedule) Set(opts ...ScheduleOption) error {
+	s.mu.Lock()
+	wasEnabled := s.enabled
+	err := s.apply(opts)
+	enabled, stateChange := s.enabled, s.stateChange
+	var wake func()
+	if err == nil && s.asleep &&
+		(s.enabled || s.pausedUntil != nil || s.disabledMode != DisabledSleep) {
+		s.asleep = false
+		wake = s.wake
+	}
+	s.mu.Unlock()
+
+	if err == nil && enabled != wasEnabled {
+		s.safeStateChange(stateChange, enabled)
+	}
+	s.safeWake(wake)
+	return err
+}
+
+// apply validates opts against a copy of the schedule and applies them if
+// they are valid. Callers must hold s.mu.
+func (s *Schedule) apply(opts []ScheduleOption) error {
 	// validate using temp var
 	temp := &Schedule{
 		enabled:          s.enabled,
+		disabledRecheck:  s.disabledRecheck,
+		disabledMode:     s.disabledMode,
+		alignMode:        s.alignMode,
+		alignAnchor:      s.alignAnchor,
+		alignOffset:      s.alignOffset,
+		jitter:           s.jitter,
+		splayKey:         s.splayKey,
+		splayMax:         s.splayMax,
+		splay:            s.splay,
+		nthDay:           s.nthDay,
+		monthEnd:         s.monthEnd,
+		dstGap:           s.dstGap,
+		dstOverlap:       s.dstOverlap,
 		interval:         s.interval,
 		intervalTimeUnit: s.intervalTimeUnit,
 		precision:        s.precision,
+		calendar:         s.calendar,
+		maxRuns:          s.maxRuns,
+		remainingRuns:    s.remainingRuns,
 	}
 
 	// Only copy pointers that exist
@@ -115,6 +253,10 @@ func (s *Schedule) Set(opts ...ScheduleOption) error {
 		copy := *s.startDate
 		temp.startDate = &copy
 	}
+	if s.endDate != nil {
+		copy := *s.endDate
+		temp.endDate = &copy
+	}
 	if s.startTime != nil {
 		copy := *s.startTime
 		temp.startTime = &copy
@@ -123,6 +265,12 @@ func (s *Schedule) Set(opts ...ScheduleOption) error {
 		copy := *s.endTime
 		temp.endTime = &copy
 	}
+	if s.timeWindows != nil {
+		temp.timeWindows = append([]TimeWindow(nil), s.timeWindows...)
+	}
+	if s.weeklyWindows != nil {
+		temp.weeklyWindows = copyWeeklyWindows(s.weeklyWindows)
+	}
 
 	// For map, consider if we really need to back it up
 	if s.allowedWeekdays != nil {
@@ -153,110 +301,300 @@ func (s *Schedule) Set(opts ...ScheduleOption) error {
 //
 // The evaluation follows this priority order:
 //  1. Execute before-hook if set
-//  2. If disabled, return t + 5 minutes (for periodic re-checking)
+//  2. If disabled, return t + the recheck interval (5 minutes by default) for
+//     periodic re-checking. A paused schedule is rechecked no later than the
+//     end of its pause; once t reaches it, the schedule is enabled again and
+//     the state change hook is called. In DisabledSleep mode, return the zero
+//     time instead, or for a paused schedule continue from the end of the
+//     pause, so the first run after it is scheduled directly.
 //  3. If nextRun is cached and still future, return it
 //  4. If startDate is set and t is before it:
-//     - Return startDate + startTime if both set
+//     - Return startDate + first window start if a time window is set
 //     - Otherwise return startDate
-//  5. If today is not allowed, find the next allowed day.
-//  6. If startTime is set (daily time window):
+//  5. If no time window is set and today is not allowed, find the next allowed day.
+//  6. If a time window is set (startTime, SetTimeWindows or SetWeeklyTimeWindows):
 //     a. Precision mode: strict intervals within window, next day if overflow
 //     b. Non-precision mode: round up from startTime using intervals
-//  7. Otherwise: calculate next run using intervals from current time
-//  8. Execute after-hook and cache result
+//     With several windows the earliest eligible slot across all of them wins.
+//     Windows whose end is not after their start wrap past midnight and are
+//     owned, for weekday filtering, by the day they opened.
+//  7. Otherwise: calculate next run using intervals from current time, or
+//     the next point of the alignment grid (AlignToClock, AlignToAnchor)
+//     With SetJitter or SetSplay the run is then delayed, without leaving
+//     its time window or day, and later runs are computed as if it was not.
+//     With an nth day of the month, steps 4-7 instead find the nth allowed
+//     day of every interval-th month from the start date, and run at its
+//     first window opening or at midnight, not before the start date.
+//  8. If endDate is set and the result is after it, return the zero time,
+//     which robfig/cron treats as "never run again"
+//  9. If maxRuns is set, count the run. Once no runs remain, return the zero time
+//  10. Execute after-hook and cache result. After the end date or the last run
+//     the hook receives the zero time.
+//
+// Explain reports which of these steps produced a run.
 //
-// Time zones are handled by converting all times to t's location.
+// Time zones are handled by converting all times to the location set with
+// SetLocation, or to t's location if none is set. The result is returned in
+// t's location.
 func (s *Schedule) Next(t time.Time) time.Time {
 	//  1. Run pre-hook
-	s.safeBeforeNext(s.beforeNext)
+	s.mu.Lock()
+	beforeNext := s.beforeNext
+	s.mu.Unlock()
+	s.safeBeforeNext(beforeNext)
+
+	s.mu.Lock()
+	resumed := s.resumeAt(t)
+	next, computed := s.nextRunAfter(t)
+	if computed {
+		// Cache the run before unlocking, so a SetNextRun during the hook wins
+		s.setNextRun(&next)
+	}
+	cached := s.nextRun
+	s.asleep = next.IsZero() && s.isDisabledAt(t)
+	afterNext, stateChange := s.afterNext, s.stateChange
+	s.mu.Unlock()
+	if resumed {
+		s.safeStateChange(stateChange, true)
+	}
+	if !computed {
+		return next
+	}
 
-	//  2. If the schedule is disabled, schedule the next check 5 minutes later.
-	if !s.enabled {
-		return t.Add(5 * time.Minute)
+	// 10. Run post-hook.
+	s.safeAfterNext(afterNext, &next, cached)
+
+	return next
+}
+
+// nextRunAfter applies steps 2-9 of Next. computed is false when the result
+// comes from steps 2 or 3, which skip the post-hook. Callers must hold s.mu.
+func (s *Schedule) nextRunAfter(t time.Time) (next time.Time, computed bool) {
+	// Evaluate in the schedule's own location and answer in t's
+	if s.location != nil {
+		caller := t.Location()
+		t = t.In(s.location)
+		defer func() { next = inLocation(next, caller) }()
+	}
+
+	//  2. If the schedule is disabled, schedule the next check, or sleep.
+	if s.isDisabledAt(t) {
+		switch {
+		case s.disabledMode != DisabledSleep:
+			return s.recheckAfter(t), false
+		case s.pausedUntil == nil:
+			s.trace.step(2, RuleDisabled, "schedule is disabled and sleeps")
+			return time.Time{}, false
+		}
+		s.trace.step(2, RuleDisabled, "schedule is paused until %s, continue from there",
+			formatTraceTime(*s.pausedUntil))
+		t = s.pausedUntil.In(t.Location())
 	}
 
 	//  3. If nextRun is still in the future, return it directly.
-	if s.nextRun.After(t) {
-		return s.nextRun
+	if s.nextRun.After(t) && !s.isPastEndDate(s.nextRun) {
+		s.trace.step(3, RuleNextRun, "cached next run is still ahead")
+		s.trace.propose(s.nextRun, RuleNextRun, "")
+		return s.nextRun, false
 	}
 
-	var next time.Time
-	//  8. Run post-hook.
-	defer s.safeAfterNext(s.afterNext, &next)
+	//  4-7. Compute the next run, then delay it by the jitter and splay.
+	s.anchorMonthDay(t)
+	next = s.withJitter(s.next(s.jitterFrom(t)), t)
 
-	//  4. If StartDate is set and t is before it:
-	//     - If StartTime is also set and still in the future, return StartDate+StartTime.
-	//     - Otherwise, return StartDate.
-	if s.startDate != nil && t.Before(*s.startDate) {
-		next = s.startDate.In(t.Location())
-		if s.startTime != nil {
-			return combineDayAndTime(next, s.startTime.In(next.Location()))
-		}
-		return next
+	//  8. If the next run passes EndDate, the schedule has expired.
+	if s.isPastEndDate(next) {
+		s.trace.step(8, RuleEndDate, "schedule expired")
+		s.trace.rejectLast(RuleEndDate, "after the end date %s", formatTraceTime(*s.endDate))
+		next = time.Time{}
 	}
 
-	// 5. Check if today is an allowed day
-	if !s.isDayAllowed(t) {
-		// Skip to next allowed day at the start time of next 24 hour
-		next = s.findNextAllowedDay(t.Add(24 * time.Hour))
-		return next
+	//  9. If MaxRuns is set, count each distinct run until none remain.
+	if s.maxRuns > 0 && !next.IsZero() && !next.Equal(s.lastRun) {
+		if s.remainingRuns < 1 {
+			s.trace.step(9, RuleRunLimit, "all %d runs are used", s.maxRuns)
+			s.trace.rejectLast(RuleRunLimit, "no runs remain")
+			next = time.Time{}
+		} else {
+			s.remainingRuns--
+			s.lastRun = next
+			s.trace.step(9, RuleRunLimit, "%d of %d runs remain", s.remainingRuns, s.maxRuns)
+		}
 	}
 
-	//  6. If StartTime is set (time-of-day window):
-	//     a. If t is before today's STime, return today's STime.
-	//     b. If t is after today's ETime (or default 23:59:59), return tomorrow's STime.
-	if s.startTime != nil {
-		startTime := combineDayAndTime(t, s.startTime.In(t.Location()))
+	return next, true
+}
 
-		var endTime time.Time
-		if s.endTime != nil {
-			endTime = combineDayAndTime(t, s.endTime.In(t.Location()))
-		} else {
-			endTime = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
+// isDisabledAt reports whether the schedule is disabled at t. A paused
+// schedule is disabled until the end of its pause.
+func (s *Schedule) isDisabledAt(t time.Time) bool {
+	return !s.enabled && (s.pausedUntil == nil || t.Before(*s.pausedUntil))
+}
+
+// resumeAt enables a paused schedule once t reaches the end of its pause and
+// reports whether it did. Callers must hold s.mu.
+func (s *Schedule) resumeAt(t time.Time) bool {
+	if s.enabled || s.pausedUntil == nil || t.Before(*s.pausedUntil) {
+		return false
+	}
+	s.enabled = true
+	s.pausedUntil = nil
+	return true
+}
+
+// recheckAfter returns when a disabled schedule is checked again after t:
+// after the recheck interval, or at the end of the pause if that is sooner.
+func (s *Schedule) recheckAfter(t time.Time) time.Time {
+	interval := s.disabledRecheck
+	if interval == 0 {
+		interval = defaultDisabledRecheck
+	}
+	recheck := t.Add(interval)
+
+	if s.pausedUntil != nil {
+		s.trace.step(2, RuleDisabled, "schedule is paused until %s",
+			formatTraceTime(*s.pausedUntil))
+		if s.pausedUntil.Before(recheck) {
+			s.trace.propose(*s.pausedUntil, RuleDisabled, "the pause ends")
+			return *s.pausedUntil
 		}
+	} else {
+		s.trace.step(2, RuleDisabled, "schedule is disabled")
+	}
+	s.trace.propose(recheck, RuleDisabled, "recheck in %s", interval)
+	return recheck
+}
 
-		if s.precision {
-			// use the earliest stime
-			if t.Before(startTime) { // 6a
-				next = startTime
-			} else { // 6b
-				next = s.incrementInterval(t)
-			}
-		} else { // 7. Otherwise, rounding next run based on the Interval and ItvUnit
-			next = startTime
-			for next.Before(t) {
-				next = s.incrementInterval(next)
-			}
+// RemainingRuns returns how many more distinct run times Next will produce
+// before returning the zero time. Returns -1 if no run limit is set.
+//
+// Example:
+//
+//	if schedule.RemainingRuns() == 0 {
+//	    // reset the counter
+//	    schedule.Set(SetRemainingRuns(5))
+//	}
+func (s *Schedule) RemainingRuns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxRuns < 1 {
+		return -1
+	}
+	return s.remainingRuns
+}
+
+// next computes the next run after t (steps 4-7 of Next) without touching the
+// nextRun cache or calling hooks. If the calendar is a RangeCalendar, runs that
+// fall inside a blocked time range are recomputed from the end of that range.
+func (s *Schedule) next(t time.Time) time.Time {
+	next := s.compute(t)
+
+	ranges, ok := s.calendar.(RangeCalendar)
+	if !ok {
+		return next
+	}
+
+	for i := 0; i < maxBlockedDays && !next.IsZero(); i++ {
+		until, blocked := ranges.BlockedUntil(next)
+		if !blocked {
+			return next
 		}
+		s.trace.rejectLast(RuleCalendar, "blocked until %s", formatTraceTime(until))
+		next = s.compute(until)
+	}
+
+	// Still blocked after the safety limit
+	return time.Time{}
+}
+
+// compute applies steps 4-7 of Next to find the next run after t.
+func (s *Schedule) compute(t time.Time) time.Time {
+	if s.nthDay != 0 {
+		return s.nextNthDay(t)
+	}
 
-		// Past end time, move to next allowed day
-		if next.Day() != t.Day() { // increment moved time to different day
+	//  4. If StartDate is set and t is before it:
+	//     - If a time window is also set, return StartDate+first window start.
+	//     - Otherwise, return StartDate.
+	//     - With alignment, move it to the next point of the alignment grid.
+	if s.startDate != nil && t.Before(*s.startDate) {
+		s.trace.step(4, RuleStartDate, "before the start date %s", formatTraceTime(*s.startDate))
+		next := s.startDate.In(t.Location())
+		if spans := s.windowsOn(next); len(spans) > 0 {
+			next = spans[0].open
+		} else if s.hasWindows() {
+			s.trace.reject(startOfDay(next), RuleStartDate, RuleTimeWindow, "no time window")
 			next = s.findNextAllowedDay(next)
-		} else if next.After(endTime) {
-			next = s.findNextAllowedDay(startTime.Add(24 * time.Hour))
 		}
+		next = s.alignStart(next)
+		s.trace.propose(next, RuleStartDate, "")
+		return next
+	}
 
+	//  5. Check if today is an allowed day. With a time window the weekday check
+	//     happens per window in step 6, since a window that opened yesterday
+	//     may still be open today.
+	if !s.hasWindows() && !s.isDayAllowed(t) {
+		s.trace.step(5, RuleWeekday, "t falls on a day that is not allowed")
+		s.trace.rejectDay(s, t, RuleWeekday)
+		// Skip to the start of the next allowed day
+		next := s.alignStart(s.findNextAllowedDay(startOfNextDay(t)))
+		s.trace.propose(next, RuleWeekday, "start of the next allowed day")
 		return next
 	}
 
+	//  6. If a time-of-day window is set:
+	//     a. If t is before the window opens, return the window's start.
+	//     b. If t is inside the window, step by interval (precision) or round
+	//        up from the window's start (non-precision).
+	//     c. If the result passes the window's end, move to the next window.
+	if s.hasWindows() {
+		if s.precision {
+			s.trace.step(6, RuleTimeWindow, "precision mode, step %s from t",
+				formatTraceInterval(s.interval, s.intervalTimeUnit))
+		} else {
+			s.trace.step(6, RuleTimeWindow, "non-precision mode, round up from the window start by %s",
+				formatTraceInterval(s.interval, s.intervalTimeUnit))
+		}
+		return s.nextInWindow(t)
+	}
+
 	//  7. Otherwise, compute the next run based on Interval and ItvUnit
 	//     (seconds, minutes, hours, days, weeks, months, years).
 	//     If no valid unit is provided, default to 5 minutes.
-	next = s.incrementInterval(t)
+	//     With alignment, take the next point of the alignment grid instead.
+	if s.alignMode != AlignNone {
+		return s.nextAligned(t)
+	}
+	next := s.incrementInterval(t)
+	s.trace.step(7, RuleInterval, "add %s to t", formatTraceInterval(s.interval, s.intervalTimeUnit))
 
 	// Apply weekday filtering if the day changed
 	if next.Day() != t.Day() || next.Month() != t.Month() || next.Year() != t.Year() {
-		next = s.findNextAllowedDay(next)
+		if snapped := s.findNextAllowedDay(next); !snapped.Equal(next) {
+			s.trace.reject(next, RuleInterval, RuleWeekday, "the day changed, runs start at midnight")
+			s.trace.propose(snapped, RuleWeekday, "start of the next allowed day")
+			return snapped
+		}
 	}
 
+	s.trace.propose(next, RuleInterval, "")
 	return next
 }
 
+// isPastEndDate reports whether t falls after the configured end date.
+// Always false when no end date is set.
+func (s *Schedule) isPastEndDate(t time.Time) bool {
+	return s.endDate != nil && t.After(*s.endDate)
+}
+
 // incrementInterval calculates the next time by adding the configured interval
 // to the given time t. The calculation method depends on the intervalTimeUnit:
 // - Second/Minute/Hour: adds duration using time.Add()
-// - Day/Week: adds calendar days using time.AddDate()
-// - Month/Year: adds calendar months/years using time.AddDate()
+// - Day/Week: adds calendar days, keeping the wall-clock time (see addDate)
+// - Month/Year: adds calendar months/years, following the month end policy
+// - BusinessDay: adds allowed days, skipping the others (see addBusinessDays)
 //
 // For invalid intervalTimeUnit values, defaults to adding 5 minutes.
 func (s *Schedule) incrementInterval(t time.Time) time.Time {
@@ -268,13 +606,15 @@ func (s *Schedule) incrementInterval(t time.Time) time.Time {
 	case Hour:
 		return t.Add(time.Duration(s.interval) * time.Hour)
 	case Day:
-		return t.AddDate(0, 0, s.interval)
+		return addDate(t, 0, 0, s.interval)
 	case Week:
-		return t.AddDate(0, 0, s.interval*7)
+		return addDate(t, 0, 0, s.interval*7)
 	case Month:
-		return t.AddDate(0, s.interval, 0)
+		return s.addMonths(t, s.interval)
 	case Year:
-		return t.AddDate(s.interval, 0, 0)
+		return s.addMonths(t, s.interval*12)
+	case BusinessDay:
+		return s.addBusinessDays(t, s.interval)
 	default: // default 5 minutes
 		return t.Add(5 * time.Minute)
 	}
@@ -283,6 +623,7 @@ func (s *Schedule) incrementInterval(t time.Time) time.Time {
 // setNextRun caches the calculated next run time for efficiency.
 // This cached value is returned by Next() if it's still in the future,
 // avoiding recalculation on subsequent calls with the same or earlier time.
+// Callers must hold s.mu.
 func (s *Schedule) setNextRun(nextRun *time.Time) {
 	if nextRun == nil {
 		s.nextRun = time.Time{}
@@ -298,19 +639,74 @@ func validate(s *Schedule) error {
 		return ErrInvalidInterval
 	}
 
+	if s.maxRuns < 0 || s.remainingRuns < 0 || s.remainingRuns > s.maxRuns {
+		return ErrInvalidRunLimit
+	}
+
+	if s.disabledRecheck < 0 {
+		return ErrInvalidRecheckInterval
+	}
+
+	if !disabledModeNames.has(s.disabledMode) {
+		return ErrInvalidDisabledMode
+	}
+
+	if err := validateAlignment(s); err != nil {
+		return err
+	}
+	if err := validateJitter(s); err != nil {
+		return err
+	}
+
+	if s.nthDay < -31 || s.nthDay > 31 || (s.nthDay != 0 && s.intervalTimeUnit != Month) {
+		return ErrInvalidNthDay
+	}
+
+	if !monthEndPolicyNames.has(s.monthEnd) {
+		return ErrInvalidMonthEndPolicy
+	}
+
+	if (s.dstGap != DSTShiftForward && s.dstGap != DSTSkip) ||
+		(s.dstOverlap != DSTRunOnce && s.dstOverlap != DSTRunTwice) {
+		return ErrInvalidDSTPolicy
+	}
+
+	if s.startDate != nil && s.endDate != nil && s.endDate.Before(*s.startDate) {
+		return ErrInvalidDateRange
+	}
+
+	// start after end is an overnight window, equal is ambiguous
 	if s.startTime != nil && s.endTime != nil {
-		startSeconds := s.startTime.Hour()*3600 + s.startTime.Minute()*60 + s.startTime.Second()
-		endSeconds := s.endTime.Hour()*3600 + s.endTime.Minute()*60 + s.endTime.Second()
-		if startSeconds >= endSeconds {
+		if clockSeconds(*s.startTime) == clockSeconds(*s.endTime) {
 			return ErrInvalidTimeWindow
 		}
 	}
 
+	if len(s.timeWindows) > 0 {
+		if s.startTime != nil || s.endTime != nil {
+			return ErrConflictingTimeWindows
+		}
+		if err := validateTimeWindows(s.timeWindows); err != nil {
+			return err
+		}
+	}
+
+	if s.weeklyWindows != nil {
+		if s.startTime != nil || s.endTime != nil || len(s.timeWindows) > 0 {
+			return ErrConflictingTimeWindows
+		}
+		if err := validateWeeklyTimeWindows(s.weeklyWindows); err != nil {
+			return err
+		}
+	}
+
 	if s.allowedWeekdays != nil &&
 		len(
 			*s.allowedWeekdays,
 		) > 0 { // If using week-based or longer intervals with weekday restrictions, warn about potential issues
-		if s.intervalTimeUnit == Week || s.intervalTimeUnit == Month || s.intervalTimeUnit == Year {
+		// An nth day of the month counts the allowed weekdays instead
+		if s.intervalTimeUnit == Week || s.intervalTimeUnit == Year ||
+			(s.intervalTimeUnit == Month && s.nthDay == 0) {
 			return ErrMultiIntervalWithWeekdayWindow
 		}
 	}
@@ -318,58 +714,64 @@ func validate(s *Schedule) error {
 	return nil
 }
 
-// isDayAllowed checks if the given time falls on an allowed weekday.
+// isDayAllowed checks if the given time falls on an allowed weekday and is not
+// blocked by the calendar.
 // Returns true if no weekday restrictions are set (allowedWeekdays is nil)
-// or if the day matches one of the allowed weekdays.
+// or if the day matches one of the allowed weekdays, and the calendar (if any)
+// does not block the date. Without weekday restrictions, the BusinessDay unit
+// allows Monday to Friday.
 func (s *Schedule) isDayAllowed(t time.Time) bool {
+	if s.isBlocked(t) {
+		return false
+	}
+
 	if s.allowedWeekdays == nil {
-		return true
+		// Business days default to Monday to Friday
+		return s.intervalTimeUnit != BusinessDay ||
+			(t.Weekday() != time.Saturday && t.Weekday() != time.Sunday)
 	}
 
 	return (*s.allowedWeekdays)[t.Weekday()]
 }
 
-// findNextAllowedDay finds the next date that satisfies weekday restrictions.
-// Starts from the given time and checks up to 14 days ahead to find an allowed day.
-// If startTime is configured, returns the allowed day at startTime, otherwise at midnight.
+// findNextAllowedDay finds the next date that satisfies weekday and calendar
+// restrictions, starting from the given time.
+// If a time window is configured, returns the allowed day at the first window's
+// start, skipping days that have no window (weekly time windows), otherwise at
+// midnight.
 //
+// Days rejected by weekday or window rules count toward maxScanDays, days
+// blocked by the calendar count toward maxBlockedDays.
 // Returns the original start time as fallback if no allowed day found (should not happen).
 func (s *Schedule) findNextAllowedDay(start time.Time) time.Time {
 	current := start
 
-	// Safety limit to prevent infinite loops (check up to 14 days)
-	for i := 0; i < 14; i++ {
+	// Safety limits to prevent infinite loops
+	for rejected, blocked := 0, 0; rejected < maxScanDays && blocked < maxBlockedDays; {
 		if s.isDayAllowed(current) {
-			// If we have start/end times
-			if s.startTime != nil {
-				return combineDayAndTime(current, s.startTime.In(current.Location()))
+			// If we have time windows
+			if spans := s.windowsOn(current); len(spans) > 0 {
+				return spans[0].open
 			}
-			// else return midnight
-			return time.Date(
-				current.Year(),
-				current.Month(),
-				current.Day(),
-				0,
-				0,
-				0,
-				0,
-				current.Location(),
-			)
-		}
-
-		// Move to next day
-		if s.startTime != nil {
-			// Jump to start time of next day
-			current = combineDayAndTime(
-				current.Add(24*time.Hour),
-				s.startTime.In(current.Location()),
-			)
+			// else return midnight, unless this day has no window of its own
+			if !s.hasWindows() {
+				return startOfDay(current)
+			}
+			s.trace.reject(startOfDay(current), RuleWeekday, RuleTimeWindow, "no time window")
+			rejected++
+		} else if s.isBlocked(current) {
+			s.trace.rejectDay(s, current, RuleWeekday)
+			blocked++
 		} else {
-			current = current.Add(24 * time.Hour)
+			s.trace.rejectDay(s, current, RuleWeekday)
+			rejected++
 		}
+
+		// Move to next day by date, as days are 23 or 25 hours long across DST
+		current = startOfNextDay(current)
 	}
 
-	// Fallback: if no allowed day found in 2 weeks, return original time
+	// Fallback: if no allowed day found, return original time
 	// This should never happen with valid configurations
 	return start
 }
@@ -390,11 +792,49 @@ func (s *Schedule) safeBeforeNext(beforeNext func(*Schedule)) {
 	beforeNext(s)
 }
 
+// safeStateChange executes the stateChange hook function with panic recovery.
+// The hook runs without holding s.mu.
+func (s *Schedule) safeStateChange(stateChange func(enabled bool), enabled bool) {
+	if stateChange == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("stateChange() panicked. %v", r)
+		}
+	}()
+
+	stateChange(enabled)
+}
+
+// safeWake executes the wake hook function with panic recovery. The hook runs
+// without holding s.mu.
+func (s *Schedule) safeWake(wake func()) {
+	if wake == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("wake() panicked. %v", r)
+		}
+	}()
+
+	wake()
+}
+
 // safeAfterNext executes the afterNext hook function with panic recovery
 // and ensures the nextRun cache is updated regardless of hook success/failure.
 // The nextRun cache update happens in a defer to guarantee execution.
-func (s *Schedule) safeAfterNext(afterNext func(*time.Time), nextRun *time.Time) {
-	defer s.setNextRun(nextRun)
+// The hook runs without holding s.mu; the cache update takes it and is
+// skipped if the cache no longer holds cached, as set by Next before the hook.
+func (s *Schedule) safeAfterNext(afterNext func(*time.Time), nextRun *time.Time, cached time.Time) {
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.nextRun.Equal(cached) {
+			s.setNextRun(nextRun)
+		}
+	}()
 	if afterNext == nil {
 		return
 	}
@@ -407,21 +847,15 @@ func (s *Schedule) safeAfterNext(afterNext func(*time.Time), nextRun *time.Time)
 	afterNext(nextRun)
 }
 
-// combineDayAndTime combines date components from 'day' with time components from 't'.
-// Useful for applying a specific time-of-day to a different date while preserving
-// the target date's timezone location.
-//
-// Example: day=2024-03-15 10:00:00 UTC, t=2000-01-01 14:30:00 UTC
-// Result: 2024-03-15 14:30:00 UTC
-func combineDayAndTime(day time.Time, t time.Time) time.Time {
-	return time.Date(
-		day.Year(),
-		day.Month(),
-		day.Day(),
-		t.Hour(),
-		t.Minute(),
-		t.Second(),
-		t.Nanosecond(),
-		day.Location(),
-	)
+// inLocation returns t in loc, keeping the zero time as is.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(loc)
+}
+
+// endOfDay returns the last representable instant of t's date in t's location.
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
 }
@@ -10,7 +10,8 @@ import (
 
 func TestNew_BasicValidation(t *testing.T) {
 	startTime := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
-	endTime := time.Date(2000, 1, 1, 9, 0, 0, 0, time.UTC)
+	endTime := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC)
+	overnightEndTime := time.Date(2000, 1, 1, 4, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name          string
@@ -48,6 +49,16 @@ func TestNew_BasicValidation(t *testing.T) {
 			},
 			expectError: ErrInvalidTimeWindow,
 		},
+		{
+			name:     "overnight time window",
+			interval: 5,
+			unit:     Second,
+			opts: []ScheduleOption{
+				SetStartTime(&startTime),
+				SetEndTime(&overnightEndTime),
+			},
+			expectEnabled: true,
+		},
 		{
 			name:     "empty weekdays",
 			interval: 5,
@@ -324,6 +335,117 @@ func TestSchedule_DisabledSchedule(t *testing.T) {
 	assert.WithinDuration(t, expected, next, time.Second)
 }
 
+func TestSchedule_DisabledRecheckInterval(t *testing.T) {
+	schedule, err := New(5, Second, Disable(), SetDisabledRecheckInterval(30*time.Second))
+	require.NoError(t, err)
+
+	now := parseTime(t, "2024-03-11 10:00:00")
+	assert.Equal(t, parseTime(t, "2024-03-11 10:00:30"), schedule.Next(now))
+
+	// 0 restores the default
+	require.NoError(t, schedule.Set(SetDisabledRecheckInterval(0)))
+	assert.Equal(t, parseTime(t, "2024-03-11 10:05:00"), schedule.Next(now))
+
+	assert.ErrorIs(t, schedule.Set(SetDisabledRecheckInterval(-time.Second)),
+		ErrInvalidRecheckInterval)
+}
+
+func TestSchedule_PauseUntil(t *testing.T) {
+	var changes []bool
+	schedule, err := New(1, Hour,
+		SetDisabledRecheckInterval(time.Hour),
+		SetStateChangeFunc(func(enabled bool) { changes = append(changes, enabled) }),
+	)
+	require.NoError(t, err)
+
+	pausedUntil := parseTime(t, "2024-03-11 12:30:00")
+	require.NoError(t, schedule.Set(PauseUntil(pausedUntil)))
+	assert.Equal(t, []bool{false}, changes)
+
+	// Rechecked hourly, and exactly when the pause ends
+	assert.Equal(t, parseTime(t, "2024-03-11 11:00:00"),
+		schedule.Next(parseTime(t, "2024-03-11 10:00:00")))
+	assert.Equal(t, pausedUntil, schedule.Next(parseTime(t, "2024-03-11 12:00:00")))
+	assert.Equal(t, []bool{false}, changes)
+
+	// Resumes without another Set
+	assert.Equal(t, parseTime(t, "2024-03-11 13:30:00"), schedule.Next(pausedUntil))
+	assert.Equal(t, []bool{false, true}, changes)
+	assert.True(t, schedule.enabled)
+	assert.Nil(t, schedule.pausedUntil)
+
+	// Enable ends a pause early, Disable pauses indefinitely
+	require.NoError(t, schedule.Set(PauseUntil(parseTime(t, "2024-03-12 00:00:00"))))
+	require.NoError(t, schedule.Set(Enable()))
+	assert.Nil(t, schedule.pausedUntil)
+	require.NoError(t, schedule.Set(Disable()))
+	assert.Equal(t, parseTime(t, "2024-03-12 01:00:00"),
+		schedule.Next(parseTime(t, "2024-03-12 00:00:00")))
+	assert.Equal(t, []bool{false, true, false, true, false}, changes)
+}
+
+func TestSchedule_StateChangeHook(t *testing.T) {
+	calls := 0
+	schedule, err := New(5, Second, SetStateChangeFunc(func(bool) { calls++ }))
+	require.NoError(t, err)
+
+	// Options that keep the state do not call the hook
+	require.NoError(t, schedule.Set(Enable(), SetInterval(10)))
+	assert.Equal(t, 0, calls)
+
+	// Nor do rejected updates
+	assert.Error(t, schedule.Set(Disable(), SetInterval(0)))
+	assert.Equal(t, 0, calls)
+
+	// The hook may call Set and panics are recovered
+	require.NoError(t, schedule.Set(SetStateChangeFunc(func(enabled bool) {
+		calls++
+		if !enabled {
+			assert.NoError(t, schedule.Set(SetInterval(20)))
+			panic("hook failure")
+		}
+	})))
+	require.NoError(t, schedule.Set(Disable()))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 20, schedule.interval)
+}
+
+func TestSchedule_SetLocation(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Sunday 20:00 UTC is already Monday 03:00 in Jakarta
+	sunday := parseTime(t, "2025-01-12 20:00:00")
+
+	follows, err := New(1, Hour, SetAllowedWeekdays(time.Monday))
+	require.NoError(t, err)
+	assert.Equal(t, parseTime(t, "2025-01-13 00:00:00"), follows.Next(sunday))
+
+	pinned, err := New(1, Hour, SetAllowedWeekdays(time.Monday), SetLocation(jakarta))
+	require.NoError(t, err)
+	assert.Equal(t, parseTime(t, "2025-01-12 21:00:00"), pinned.Next(sunday))
+
+	// The same instant gives the same run, in the caller's location
+	next := pinned.Next(sunday.In(newYork))
+	assert.Equal(t, newYork, next.Location())
+	assert.True(t, parseTime(t, "2025-01-12 21:00:00").Equal(next))
+
+	runs := pinned.NextN(sunday.In(newYork), 2)
+	require.Len(t, runs, 2)
+	assert.Equal(t, newYork, runs[1].Location())
+	assert.True(t, parseTime(t, "2025-01-12 22:00:00").Equal(runs[1]))
+
+	prev := pinned.Prev(sunday)
+	assert.Equal(t, time.UTC, prev.Location())
+	assert.Equal(t, parseTime(t, "2025-01-12 19:00:00"), prev)
+
+	// Reset to follow the caller
+	require.NoError(t, pinned.Set(SetLocation(nil), SetNextRun(nil)))
+	assert.Equal(t, parseTime(t, "2025-01-13 00:00:00"), pinned.Next(sunday))
+}
+
 func TestSchedule_StartDateFuture(t *testing.T) {
 	dubaiLoc, _ := time.LoadLocation("Asia/Dubai")
 	futureDate := time.Date(2025, 3, 15, 14, 30, 0, 0, time.Local)
@@ -726,6 +848,191 @@ func TestSchedule_CriticalEdgeCases(t *testing.T) {
 	})
 }
 
+func TestSchedule_OvernightWindow(t *testing.T) {
+	startTime := time.Date(2000, 1, 1, 22, 0, 0, 0, time.UTC)
+	endTime := time.Date(2000, 1, 1, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		precision bool
+		weekdays  []time.Weekday
+		current   string
+		expected  string
+	}{
+		{
+			name:      "before window opens",
+			precision: true,
+			current:   "2024-03-11 21:00:00", // Monday
+			expected:  "2024-03-11 22:00:00",
+		},
+		{
+			name:      "crosses midnight inside window",
+			precision: true,
+			current:   "2024-03-11 23:55:00",
+			expected:  "2024-03-12 00:05:00",
+		},
+		{
+			name:      "early morning inside window opened yesterday",
+			precision: true,
+			current:   "2024-03-12 02:00:00",
+			expected:  "2024-03-12 02:10:00",
+		},
+		{
+			name:      "past window end moves to tonight's window",
+			precision: true,
+			current:   "2024-03-12 03:55:00",
+			expected:  "2024-03-12 22:00:00",
+		},
+		{
+			name:      "between windows",
+			precision: true,
+			current:   "2024-03-12 12:00:00",
+			expected:  "2024-03-12 22:00:00",
+		},
+		{
+			name:      "non-precision rounds up from window start",
+			precision: false,
+			current:   "2024-03-12 01:03:00",
+			expected:  "2024-03-12 01:10:00",
+		},
+		{
+			name:      "window owned by allowed day runs past midnight",
+			precision: true,
+			weekdays:  []time.Weekday{time.Monday},
+			current:   "2024-03-12 02:00:00", // Tuesday, window opened Monday
+			expected:  "2024-03-12 02:10:00",
+		},
+		{
+			name:      "window owned by disallowed day is skipped",
+			precision: true,
+			weekdays:  []time.Weekday{time.Monday},
+			current:   "2024-03-13 02:00:00", // Wednesday, window opened Tuesday
+			expected:  "2024-03-18 22:00:00", // next Monday
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []ScheduleOption{
+				SetStartTime(&startTime),
+				SetEndTime(&endTime),
+				SetAllowedWeekdays(tt.weekdays...),
+			}
+			if tt.precision {
+				opts = append(opts, EnablePrecision())
+			} else {
+				opts = append(opts, DisablePrecision())
+			}
+
+			schedule, err := New(10, Minute, opts...)
+			require.NoError(t, err)
+
+			current := parseTime(t, tt.current)
+			expected := parseTime(t, tt.expected)
+
+			next := schedule.Next(current)
+			assert.Equal(t, expected, next)
+		})
+	}
+}
+
+func TestSchedule_EndDate(t *testing.T) {
+	endDate := parseTime(t, "2024-03-11 10:00:00")
+
+	var reported []time.Time
+	schedule, err := New(30, Minute,
+		SetEndDate(&endDate),
+		SetAfterNextFunc(func(next *time.Time) {
+			reported = append(reported, *next)
+		}),
+	)
+	require.NoError(t, err)
+
+	// Last run lands exactly on the end date
+	next := schedule.Next(parseTime(t, "2024-03-11 09:30:00"))
+	assert.Equal(t, endDate, next)
+
+	// Next run would pass the end date
+	next = schedule.Next(parseTime(t, "2024-03-11 10:00:00"))
+	assert.True(t, next.IsZero())
+
+	// Stays expired
+	next = schedule.Next(parseTime(t, "2024-03-12 10:00:00"))
+	assert.True(t, next.IsZero())
+
+	require.Len(t, reported, 3)
+	assert.Equal(t, endDate, reported[0])
+	assert.True(t, reported[1].IsZero())
+	assert.True(t, reported[2].IsZero())
+}
+
+func TestSchedule_EndDateDiscardsCachedNextRun(t *testing.T) {
+	nextRun := parseTime(t, "2024-03-12 10:00:00")
+	schedule, err := New(30, Minute, SetNextRun(&nextRun))
+	require.NoError(t, err)
+
+	endDate := parseTime(t, "2024-03-11 12:00:00")
+	require.NoError(t, schedule.Set(SetEndDate(&endDate)))
+
+	next := schedule.Next(parseTime(t, "2024-03-11 10:00:00"))
+	assert.Equal(t, parseTime(t, "2024-03-11 10:30:00"), next)
+}
+
+func TestSchedule_EndDateValidation(t *testing.T) {
+	startDate := parseTime(t, "2024-03-11 00:00:00")
+	endDate := parseTime(t, "2024-03-10 00:00:00")
+
+	schedule, err := New(1, Hour, SetStartDate(&startDate), SetEndDate(&endDate))
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	assert.Nil(t, schedule)
+}
+
+func TestSchedule_MaxRuns(t *testing.T) {
+	schedule, err := New(2, Hour, SetMaxRuns(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, schedule.RemainingRuns())
+
+	current := parseTime(t, "2024-03-11 10:00:00")
+
+	next := schedule.Next(current)
+	assert.Equal(t, parseTime(t, "2024-03-11 12:00:00"), next)
+	assert.Equal(t, 2, schedule.RemainingRuns())
+
+	// Cached run is not counted again
+	next = schedule.Next(current)
+	assert.Equal(t, parseTime(t, "2024-03-11 12:00:00"), next)
+	assert.Equal(t, 2, schedule.RemainingRuns())
+
+	next = schedule.Next(next)
+	assert.Equal(t, parseTime(t, "2024-03-11 14:00:00"), next)
+	next = schedule.Next(next)
+	assert.Equal(t, parseTime(t, "2024-03-11 16:00:00"), next)
+	assert.Equal(t, 0, schedule.RemainingRuns())
+
+	// Limit reached
+	next = schedule.Next(next)
+	assert.True(t, next.IsZero())
+
+	// Reset through Set
+	require.NoError(t, schedule.Set(SetRemainingRuns(1)))
+	next = schedule.Next(parseTime(t, "2024-03-11 16:00:00"))
+	assert.Equal(t, parseTime(t, "2024-03-11 18:00:00"), next)
+	assert.Equal(t, 0, schedule.RemainingRuns())
+}
+
+func TestSchedule_MaxRunsValidation(t *testing.T) {
+	schedule, err := New(1, Hour)
+	require.NoError(t, err)
+	assert.Equal(t, -1, schedule.RemainingRuns())
+
+	assert.ErrorIs(t, schedule.Set(SetMaxRuns(-1)), ErrInvalidRunLimit)
+	assert.ErrorIs(t, schedule.Set(SetRemainingRuns(1)), ErrInvalidRunLimit)
+
+	require.NoError(t, schedule.Set(SetMaxRuns(2)))
+	assert.ErrorIs(t, schedule.Set(SetRemainingRuns(3)), ErrInvalidRunLimit)
+	assert.Equal(t, 2, schedule.RemainingRuns())
+}
+
 func TestSchedule_ManualNextRun(t *testing.T) {
 	// Simple test: 10-second intervals, pause from 10 AM to 3 PM
 	current := parseTime(t, "2024-03-11 10:00:00")
@@ -0,0 +1,206 @@
+package robfigcronschedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TraceRule names the rule of Next that proposed or rejected a candidate run.
+type TraceRule string
+
+const (
+	RuleDisabled   TraceRule = "disabled"
+	RuleNextRun    TraceRule = "next-run"
+	RuleStartDate  TraceRule = "start-date"
+	RuleWeekday    TraceRule = "weekday"
+	RuleCalendar   TraceRule = "calendar"
+	RuleTimeWindow TraceRule = "time-window"
+	RuleInterval   TraceRule = "interval"
+	RuleMonthDay   TraceRule = "month-day"
+	RuleJitter     TraceRule = "jitter"
+	RuleEndDate    TraceRule = "end-date"
+	RuleRunLimit   TraceRule = "run-limit"
+)
+
+// TraceStep is a numbered step of Next that was taken. Step uses the numbering
+// of Next's documentation.
+type TraceStep struct {
+	Step   int
+	Rule   TraceRule
+	Detail string
+}
+
+// TraceCandidate is a time considered while computing the next run. Rule is
+// the rule that proposed it. RejectedBy is the rule that rejected it, or empty
+// if it was not rejected. Days rejected as a whole are reported at midnight.
+type TraceCandidate struct {
+	Time       time.Time
+	Rule       TraceRule
+	RejectedBy TraceRule
+	Reason     string
+}
+
+// Explanation describes how Next arrives at its result for a given time.
+type Explanation struct {
+	// At is the time passed to Explain.
+	At time.Time
+	// Next is the run Next would return, without the random delay of
+	// SetJitter unless Next already drew it.
+	Next       time.Time
+	Steps      []TraceStep
+	Candidates []TraceCandidate
+}
+
+// Explain returns what Next would return for t together with a trace of the
+// steps taken and the candidate times considered. Like a preview it has no
+// side effects: hooks are not called, neither the nextRun cache nor the run
+// counter change, and no random delay is drawn for SetJitter.
+//
+// Example:
+//
+//	explanation := schedule.Explain(time.Now())
+//	fmt.Println(explanation)
+//	// Next(2025-01-10T16:30:00Z) = 2025-01-13T09:00:00Z
+//	// steps:
+//	//   6 time-window: precision mode, step 3 hours from t
+//	// candidates:
+//	//   2025-01-10T19:30:00Z interval, rejected by time-window: after the window ends at ...
+//	//   2025-01-11T00:00:00Z time-window, rejected by weekday: Saturday is not an allowed day
+//	//   2025-01-12T00:00:00Z time-window, rejected by weekday: Sunday is not an allowed day
+//	//   2025-01-13T09:00:00Z time-window: the window opens
+func (s *Schedule) Explain(t time.Time) Explanation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := Explanation{At: t}
+	s.trace = &tracer{explanation: &e}
+	defer func() { s.trace = nil }()
+
+	// Run the same code as Next, then undo the run counting and jitter
+	remainingRuns, lastRun := s.remainingRuns, s.lastRun
+	jitterBase, jitterRun := s.jitterBase, s.jitterRun
+	monthDay := s.monthDay
+	e.Next, _ = s.nextRunAfter(t)
+	s.remainingRuns, s.lastRun = remainingRuns, lastRun
+	s.jitterBase, s.jitterRun = jitterBase, jitterRun
+	s.monthDay = monthDay
+
+	return e
+}
+
+// String formats the explanation for logs, one step or candidate per line.
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Next(%s) = %s", formatTraceTime(e.At), formatTraceTime(e.Next))
+
+	if len(e.Steps) > 0 {
+		b.WriteString("\nsteps:")
+	}
+	for _, step := range e.Steps {
+		fmt.Fprintf(&b, "\n  %d %s: %s", step.Step, step.Rule, step.Detail)
+	}
+
+	if len(e.Candidates) > 0 {
+		b.WriteString("\ncandidates:")
+	}
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s %s", formatTraceTime(c.Time), c.Rule)
+		if c.RejectedBy != "" {
+			fmt.Fprintf(&b, ", rejected by %s", c.RejectedBy)
+		}
+		if c.Reason != "" {
+			fmt.Fprintf(&b, ": %s", c.Reason)
+		}
+	}
+
+	return b.String()
+}
+
+// formatTraceTime formats t as RFC 3339, or "zero" for the zero time.
+func formatTraceTime(t time.Time) string {
+	if t.IsZero() {
+		return "zero"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// formatTraceInterval formats an interval such as "1 hour" or "30 minutes".
+func formatTraceInterval(interval int, unit IntervalTimeUnit) string {
+	if interval == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", interval, unit)
+}
+
+// tracer records an Explanation. All methods do nothing on a nil tracer, so
+// Next pays no formatting cost outside Explain.
+type tracer struct {
+	explanation *Explanation
+}
+
+// step records that a numbered step of Next was taken.
+func (tr *tracer) step(n int, rule TraceRule, format string, args ...interface{}) {
+	if tr == nil {
+		return
+	}
+	tr.explanation.Steps = append(tr.explanation.Steps, TraceStep{
+		Step:   n,
+		Rule:   rule,
+		Detail: fmt.Sprintf(format, args...),
+	})
+}
+
+// propose records a candidate that was not rejected (yet).
+func (tr *tracer) propose(t time.Time, rule TraceRule, format string, args ...interface{}) {
+	if tr == nil {
+		return
+	}
+	tr.explanation.Candidates = append(tr.explanation.Candidates, TraceCandidate{
+		Time:   t,
+		Rule:   rule,
+		Reason: fmt.Sprintf(format, args...),
+	})
+}
+
+// reject records a candidate proposed by rule and rejected by rejectedBy.
+func (tr *tracer) reject(
+	t time.Time,
+	rule, rejectedBy TraceRule,
+	format string,
+	args ...interface{},
+) {
+	if tr == nil {
+		return
+	}
+	tr.explanation.Candidates = append(tr.explanation.Candidates, TraceCandidate{
+		Time:       t,
+		Rule:       rule,
+		RejectedBy: rejectedBy,
+		Reason:     fmt.Sprintf(format, args...),
+	})
+}
+
+// rejectLast marks the most recent candidate as rejected by rejectedBy.
+func (tr *tracer) rejectLast(rejectedBy TraceRule, format string, args ...interface{}) {
+	if tr == nil || len(tr.explanation.Candidates) == 0 {
+		return
+	}
+	last := &tr.explanation.Candidates[len(tr.explanation.Candidates)-1]
+	last.RejectedBy = rejectedBy
+	last.Reason = fmt.Sprintf(format, args...)
+}
+
+// rejectDay records that day, proposed by rule, was rejected by the calendar
+// or the weekday restriction.
+func (tr *tracer) rejectDay(s *Schedule, day time.Time, rule TraceRule) {
+	if tr == nil {
+		return
+	}
+	if s.isBlocked(day) {
+		tr.reject(startOfDay(day), rule, RuleCalendar,
+			"%s is blocked by the calendar", day.Format(dateLayout))
+		return
+	}
+	tr.reject(startOfDay(day), rule, RuleWeekday, "%s is not an allowed day", day.Weekday())
+}
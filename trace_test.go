@@ -0,0 +1,234 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockedRange is a RangeCalendar blocking a single time range.
+type blockedRange struct {
+	from, until time.Time
+}
+
+func (r blockedRange) IsBlocked(time.Time) bool { return false }
+
+func (r blockedRange) BlockedUntil(t time.Time) (time.Time, bool) {
+	if !t.Before(r.from) && t.Before(r.until) {
+		return r.until, true
+	}
+	return time.Time{}, false
+}
+
+func TestSchedule_Explain(t *testing.T) {
+	s, err := Parse("every 3h between 09:00-17:00 on mon-fri")
+	require.NoError(t, err)
+
+	e := s.Explain(parseTime(t, "2025-01-10 16:30:00"))
+	assert.Equal(t, parseTime(t, "2025-01-13 09:00:00"), e.Next)
+	assert.Equal(t, []TraceStep{
+		{Step: 6, Rule: RuleTimeWindow, Detail: "precision mode, step 3 hours from t"},
+	}, e.Steps)
+	assert.Equal(t, []TraceCandidate{
+		{
+			Time:       parseTime(t, "2025-01-10 19:30:00"),
+			Rule:       RuleInterval,
+			RejectedBy: RuleTimeWindow,
+			Reason:     "after the window ends at 2025-01-10T17:00:00Z",
+		},
+		{
+			Time:       parseTime(t, "2025-01-11 00:00:00"),
+			Rule:       RuleTimeWindow,
+			RejectedBy: RuleWeekday,
+			Reason:     "Saturday is not an allowed day",
+		},
+		{
+			Time:       parseTime(t, "2025-01-12 00:00:00"),
+			Rule:       RuleTimeWindow,
+			RejectedBy: RuleWeekday,
+			Reason:     "Sunday is not an allowed day",
+		},
+		{
+			Time:   parseTime(t, "2025-01-13 09:00:00"),
+			Rule:   RuleTimeWindow,
+			Reason: "the window opens",
+		},
+	}, e.Candidates)
+
+	assert.Equal(t, `Next(2025-01-10T16:30:00Z) = 2025-01-13T09:00:00Z
+steps:
+  6 time-window: precision mode, step 3 hours from t
+candidates:
+  2025-01-10T19:30:00Z interval, rejected by time-window: after the window ends at 2025-01-10T17:00:00Z
+  2025-01-11T00:00:00Z time-window, rejected by weekday: Saturday is not an allowed day
+  2025-01-12T00:00:00Z time-window, rejected by weekday: Sunday is not an allowed day
+  2025-01-13T09:00:00Z time-window: the window opens`, e.String())
+}
+
+func TestSchedule_ExplainSteps(t *testing.T) {
+	cached := parseTime(t, "2025-01-10 12:00:00")
+	holidays := NewDateSet(parseTime(t, "2025-01-13 00:00:00"))
+	freeze := blockedRange{
+		from:  parseTime(t, "2025-01-10 10:00:00"),
+		until: parseTime(t, "2025-01-10 11:30:00"),
+	}
+	endDate := parseTime(t, "2025-01-10 11:00:00")
+
+	tests := []struct {
+		name       string
+		interval   int
+		unit       IntervalTimeUnit
+		opts       []ScheduleOption
+		at         string
+		steps      []int
+		next       string
+		rejectedBy []TraceRule
+	}{
+		{
+			name:     "disabled",
+			interval: 1,
+			unit:     Hour,
+			opts:     []ScheduleOption{Disable()},
+			at:       "2025-01-10 09:00:00",
+			steps:    []int{2},
+			next:     "2025-01-10 09:05:00",
+		},
+		{
+			name:     "cached next run",
+			interval: 1,
+			unit:     Hour,
+			opts:     []ScheduleOption{SetNextRun(&cached)},
+			at:       "2025-01-10 09:00:00",
+			steps:    []int{3},
+			next:     "2025-01-10 12:00:00",
+		},
+		{
+			name:       "disallowed day",
+			interval:   1,
+			unit:       Hour,
+			opts:       []ScheduleOption{SetAllowedWeekdays(time.Monday, time.Tuesday)},
+			at:         "2025-01-11 09:00:00",
+			steps:      []int{5},
+			next:       "2025-01-13 00:00:00",
+			rejectedBy: []TraceRule{RuleWeekday, RuleWeekday},
+		},
+		{
+			name:       "nth day of month",
+			interval:   1,
+			unit:       Month,
+			opts:       []ScheduleOption{SetAllowedWeekdays(time.Monday), SetNthDayOfMonth(5)},
+			at:         "2025-01-10 09:00:00",
+			steps:      []int{7},
+			next:       "2025-03-31 00:00:00",
+			rejectedBy: []TraceRule{RuleMonthDay, RuleMonthDay},
+		},
+		{
+			name:       "calendar date",
+			interval:   1,
+			unit:       Day,
+			opts:       []ScheduleOption{SetCalendar(holidays)},
+			at:         "2025-01-12 09:00:00",
+			steps:      []int{7},
+			next:       "2025-01-14 00:00:00",
+			rejectedBy: []TraceRule{RuleCalendar, RuleWeekday},
+		},
+		{
+			name:       "calendar range",
+			interval:   1,
+			unit:       Hour,
+			opts:       []ScheduleOption{SetCalendar(freeze)},
+			at:         "2025-01-10 09:30:00",
+			steps:      []int{7, 7},
+			next:       "2025-01-10 12:30:00",
+			rejectedBy: []TraceRule{RuleCalendar},
+		},
+		{
+			name:       "end date",
+			interval:   3,
+			unit:       Hour,
+			opts:       []ScheduleOption{SetEndDate(&endDate)},
+			at:         "2025-01-10 09:00:00",
+			steps:      []int{7, 8},
+			rejectedBy: []TraceRule{RuleEndDate},
+		},
+		{
+			name:       "run limit",
+			interval:   1,
+			unit:       Hour,
+			opts:       []ScheduleOption{SetMaxRuns(3), SetRemainingRuns(0)},
+			at:         "2025-01-10 09:00:00",
+			steps:      []int{7, 9},
+			rejectedBy: []TraceRule{RuleRunLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.interval, tt.unit, tt.opts...)
+			require.NoError(t, err)
+
+			e := s.Explain(parseTime(t, tt.at))
+
+			var steps []int
+			for _, step := range e.Steps {
+				steps = append(steps, step.Step)
+			}
+			assert.Equal(t, tt.steps, steps)
+
+			var next time.Time
+			if tt.next != "" {
+				next = parseTime(t, tt.next)
+			}
+			assert.Equal(t, next, e.Next)
+
+			var rejectedBy []TraceRule
+			for _, c := range e.Candidates {
+				if c.RejectedBy != "" {
+					rejectedBy = append(rejectedBy, c.RejectedBy)
+				}
+			}
+			assert.Equal(t, tt.rejectedBy, rejectedBy)
+		})
+	}
+}
+
+func TestSchedule_ExplainMatchesNext(t *testing.T) {
+	inputs := []string{
+		"every 45m between 08:00-12:00,22:00-02:00 on mon,wed,fri",
+		"every 1h weekly mon-fri=09:00-18:00;sat=10:00-14:00 imprecise",
+		"every 5h on tue,thu from 2025-01-08",
+		"every 20m until 2025-01-08T10:00",
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			explained, err := Parse(input)
+			require.NoError(t, err)
+			live, err := Parse(input)
+			require.NoError(t, err)
+
+			hookCalls := 0
+			require.NoError(t, explained.Set(
+				SetBeforeNextFunc(func(*Schedule) { hookCalls++ }),
+				SetAfterNextFunc(func(*time.Time) { hookCalls++ }),
+			))
+			remaining := explained.RemainingRuns()
+
+			for current := parseTime(t, "2025-01-06 07:10:00"); ; {
+				e := explained.Explain(current)
+				next := live.Next(current)
+				require.Equal(t, next, e.Next, e.String())
+				if next.IsZero() || next.After(parseTime(t, "2025-01-20 00:00:00")) {
+					break
+				}
+				current = next.Add(time.Second)
+			}
+
+			assert.Equal(t, 0, hookCalls)
+			assert.Equal(t, remaining, explained.RemainingRuns())
+			assert.True(t, explained.nextRun.IsZero())
+		})
+	}
+}
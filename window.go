@@ -0,0 +1,293 @@
+package robfigcronschedule
+
+import (
+	"sort"
+	"time"
+)
+
+// secondsPerDay is the length of a day in clock seconds, ignoring DST.
+const secondsPerDay = 24 * 60 * 60
+
+// TimeWindow is a daily time-of-day range during which the schedule may run.
+// Only the clock part (and location) of Start and End is used.
+// If End is not after Start, the window wraps past midnight and ends on the
+// following day. A zero End means the window ends at 23:59:59.
+type TimeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// span is a TimeWindow resolved to concrete instants on a given day. reopen is
+// the second occurrence of open when a fall-back transition repeats it and the
+// overlap policy is DSTRunTwice, the zero time otherwise.
+type span struct {
+	open   time.Time
+	end    time.Time
+	reopen time.Time
+}
+
+// hasWindows reports whether any daily time window is configured.
+func (s *Schedule) hasWindows() bool {
+	return s.startTime != nil || len(s.timeWindows) > 0 || s.weeklyWindows != nil
+}
+
+// windowsOn returns the time windows that open on day, resolved in day's
+// location and sorted by their open instant. Windows whose opening time is
+// skipped by DSTSkip are left out.
+func (s *Schedule) windowsOn(day time.Time) []span {
+	windows := s.timeWindows
+	if s.startTime != nil {
+		w := TimeWindow{Start: *s.startTime}
+		if s.endTime != nil {
+			w.End = *s.endTime
+		}
+		windows = []TimeWindow{w}
+	} else if s.weeklyWindows != nil {
+		windows = s.weeklyWindows[day.Weekday()]
+	}
+
+	spans := make([]span, 0, len(windows))
+	for _, w := range windows {
+		if sp, ok := s.spanOn(w, day); ok {
+			spans = append(spans, sp)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].open.Before(spans[j].open)
+	})
+	return spans
+}
+
+// spanOn resolves w to the open and end instants of the occurrence that opens
+// on day, following the DST policies. ok is false if DSTSkip skips the opening
+// time.
+func (s *Schedule) spanOn(w TimeWindow, day time.Time) (sp span, ok bool) {
+	start := w.Start.In(day.Location())
+	if sp.open, ok = s.atWallClock(day, start); !ok {
+		return span{}, false
+	}
+	sp.reopen, _ = s.repeatOf(sp.open)
+
+	if w.End.IsZero() {
+		sp.end = endOfDay(day)
+		return sp, true
+	}
+
+	end := w.End.In(day.Location())
+	year, month, date := day.Date()
+	if clockSeconds(end) <= clockSeconds(start) {
+		date++
+	}
+	times, shifted := wallClock(year, month, date,
+		end.Hour(), end.Minute(), end.Second(), end.Nanosecond(), end.Location())
+	switch {
+	case len(times) == 0:
+		sp.end = shifted
+	case s.dstOverlap == DSTRunTwice:
+		sp.end = times[len(times)-1]
+	default:
+		sp.end = times[0]
+	}
+
+	// A window inside the gap moves with its shifted opening time
+	if sp.end.Before(sp.open) {
+		length := time.Duration(clockSeconds(end)-clockSeconds(start))*time.Second +
+			time.Duration(end.Nanosecond()-start.Nanosecond())
+		sp.end = sp.open.Add(length)
+	}
+	return sp, true
+}
+
+// nextInWindow scans the daily time windows, starting with those that opened
+// yesterday, and returns the earliest run at or after t that falls inside a
+// window owned by an allowed day.
+//
+// When stepping overflows a window, the scan continues with the next window
+// that opens after it. If the step also landed on a later calendar day than the
+// window's end (multi-day intervals), the scan jumps to that day so the
+// interval is still honored.
+//
+// Returns the zero time if no window is found: days without a usable window
+// count toward maxScanDays, days blocked by the calendar toward maxBlockedDays.
+func (s *Schedule) nextInWindow(t time.Time) time.Time {
+	floor := t
+	overflowed := false
+	current := startOfDay(t).AddDate(0, 0, -1)
+	rejected, blocked := 0, 0
+
+	for ; rejected < maxScanDays && blocked < maxBlockedDays; current = current.AddDate(0, 0, 1) {
+		if !s.isDayAllowed(current) {
+			s.trace.rejectDay(s, current, RuleTimeWindow)
+			if s.isBlocked(current) {
+				blocked++
+			} else {
+				rejected++
+			}
+			continue
+		}
+
+		rejected++
+		for _, w := range s.windowsOn(current) {
+			// With alignment the window's first run is its first grid point
+			if w.open = s.alignStart(w.open); w.open.After(w.end) || w.end.Before(floor) {
+				continue
+			}
+			if w.open.After(t) && !w.open.Before(floor) {
+				s.trace.propose(w.open, RuleTimeWindow, "the window opens")
+				return w.open
+			}
+			if overflowed || w.open.After(t) {
+				continue
+			}
+
+			next := s.stepInWindow(w.open, t)
+			if w.reopen.After(t) && w.reopen.Before(next) && !w.reopen.After(w.end) {
+				s.trace.propose(w.reopen, RuleTimeWindow, "the window opens again")
+				return w.reopen
+			}
+			if !next.After(w.end) {
+				s.trace.propose(next, RuleInterval, "inside the window")
+				return next
+			}
+			s.trace.reject(next, RuleInterval, RuleTimeWindow,
+				"after the window ends at %s", formatTraceTime(w.end))
+
+			overflowed = true
+			floor = w.end
+			if nextDay := startOfDay(next); nextDay.After(startOfDay(w.end)) {
+				floor = nextDay
+				// Jump straight to the day the interval landed on
+				current = nextDay.AddDate(0, 0, -1)
+				rejected = 0
+				break
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// stepInWindow returns the next run for a t that lies inside the window opened
+// at open. Precision mode steps from t, non-precision mode rounds up from open
+// and alignment takes the next grid point after t.
+func (s *Schedule) stepInWindow(open, t time.Time) time.Time {
+	if s.alignMode != AlignNone {
+		return s.alignedAfter(t)
+	}
+	if s.precision {
+		return s.incrementInterval(t)
+	}
+	return s.roundUp(open, t)
+}
+
+// roundUp returns the first run at or after t in steps of the interval from
+// open. Units of fixed length are computed directly, calendar units are
+// stepped with roundUpByStepping.
+func (s *Schedule) roundUp(open, t time.Time) time.Time {
+	step := s.fixedInterval()
+	if step <= 0 {
+		return s.roundUpByStepping(open, t)
+	}
+	if !open.Before(t) {
+		return open
+	}
+	return open.Add((t.Sub(open) + step - 1) / step * step)
+}
+
+// roundUpByStepping is roundUp for any unit, adding the interval to open until
+// it reaches t.
+func (s *Schedule) roundUpByStepping(open, t time.Time) time.Time {
+	next := open
+	for next.Before(t) {
+		next = s.incrementInterval(next)
+	}
+	return next
+}
+
+// validateTimeWindows checks that every window has distinct start and end
+// times and that no two windows overlap on the 24-hour clock, taking windows
+// that wrap past midnight into account.
+func validateTimeWindows(windows []TimeWindow) error {
+	ranges, err := windowRanges(windows, 0)
+	if err != nil {
+		return err
+	}
+
+	if rangesOverlap(ranges, secondsPerDay) {
+		return ErrOverlappingTimeWindows
+	}
+	return nil
+}
+
+// validateWeeklyTimeWindows checks every weekday's windows like
+// validateTimeWindows, and additionally rejects overnight windows that run
+// into the next weekday's windows (Saturday wraps into Sunday).
+func validateWeeklyTimeWindows(table map[time.Weekday][]TimeWindow) error {
+	var ranges [][2]int
+	for day, windows := range table {
+		if day < time.Sunday || day > time.Saturday {
+			return ErrInvalidWeekday
+		}
+
+		dayRanges, err := windowRanges(windows, int(day)*secondsPerDay)
+		if err != nil {
+			return err
+		}
+		ranges = append(ranges, dayRanges...)
+	}
+
+	if rangesOverlap(ranges, 7*secondsPerDay) {
+		return ErrOverlappingTimeWindows
+	}
+	return nil
+}
+
+// windowRanges converts windows to [start, end) second ranges shifted by
+// offset seconds. Windows that wrap past midnight end after offset+secondsPerDay.
+func windowRanges(windows []TimeWindow, offset int) ([][2]int, error) {
+	ranges := make([][2]int, 0, len(windows))
+	for _, w := range windows {
+		start, end := clockSeconds(w.Start), secondsPerDay
+		if !w.End.IsZero() {
+			end = clockSeconds(w.End)
+		}
+		if start == end {
+			return nil, ErrInvalidTimeWindow
+		}
+		if end < start {
+			end += secondsPerDay
+		}
+		ranges = append(ranges, [2]int{offset + start, offset + end})
+	}
+	return ranges, nil
+}
+
+// copyWeeklyWindows returns a deep copy of a weekly time window table.
+func copyWeeklyWindows(table map[time.Weekday][]TimeWindow) map[time.Weekday][]TimeWindow {
+	copy := make(map[time.Weekday][]TimeWindow, len(table))
+	for day, windows := range table {
+		copy[day] = append([]TimeWindow(nil), windows...)
+	}
+	return copy
+}
+
+// rangesOverlap reports whether any two half-open [start, end) ranges overlap
+// on a circular clock of the given period.
+func rangesOverlap(ranges [][2]int, period int) bool {
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			for _, shift := range []int{-period, 0, period} {
+				a, b := ranges[i], ranges[j]
+				if a[0] < b[1]+shift && b[0]+shift < a[1] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// clockSeconds returns the number of seconds since midnight of t's clock time.
+func clockSeconds(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
@@ -0,0 +1,327 @@
+package robfigcronschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clock(hour, minute int) time.Time {
+	return time.Date(2000, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSchedule_MultipleTimeWindows(t *testing.T) {
+	windows := []TimeWindow{
+		{Start: clock(13, 0), End: clock(17, 0)}, // deliberately out of order
+		{Start: clock(8, 0), End: clock(12, 0)},
+	}
+
+	tests := []struct {
+		name      string
+		precision bool
+		current   string
+		expected  string
+	}{
+		{
+			name:      "before first window",
+			precision: true,
+			current:   "2024-03-11 07:00:00",
+			expected:  "2024-03-11 08:00:00",
+		},
+		{
+			name:      "inside first window",
+			precision: true,
+			current:   "2024-03-11 09:10:00",
+			expected:  "2024-03-11 09:40:00",
+		},
+		{
+			name:      "overflow first window moves to second",
+			precision: true,
+			current:   "2024-03-11 11:50:00",
+			expected:  "2024-03-11 13:00:00",
+		},
+		{
+			name:      "lunch break",
+			precision: true,
+			current:   "2024-03-11 12:30:00",
+			expected:  "2024-03-11 13:00:00",
+		},
+		{
+			name:      "overflow last window moves to next day",
+			precision: true,
+			current:   "2024-03-11 16:45:00",
+			expected:  "2024-03-12 08:00:00",
+		},
+		{
+			name:      "non-precision rounds up within second window",
+			precision: false,
+			current:   "2024-03-11 13:10:00",
+			expected:  "2024-03-11 13:30:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []ScheduleOption{SetTimeWindows(windows...)}
+			if tt.precision {
+				opts = append(opts, EnablePrecision())
+			} else {
+				opts = append(opts, DisablePrecision())
+			}
+
+			schedule, err := New(30, Minute, opts...)
+			require.NoError(t, err)
+
+			current := parseTime(t, tt.current)
+			expected := parseTime(t, tt.expected)
+
+			next := schedule.Next(current)
+			assert.Equal(t, expected, next)
+		})
+	}
+}
+
+func TestSchedule_TimeWindowsValidation(t *testing.T) {
+	startTime := clock(9, 0)
+
+	tests := []struct {
+		name        string
+		opts        []ScheduleOption
+		expectError error
+	}{
+		{
+			name: "adjacent windows",
+			opts: []ScheduleOption{SetTimeWindows(
+				TimeWindow{Start: clock(8, 0), End: clock(12, 0)},
+				TimeWindow{Start: clock(12, 0), End: clock(17, 0)},
+			)},
+		},
+		{
+			name: "overlapping windows",
+			opts: []ScheduleOption{SetTimeWindows(
+				TimeWindow{Start: clock(8, 0), End: clock(12, 0)},
+				TimeWindow{Start: clock(11, 0), End: clock(17, 0)},
+			)},
+			expectError: ErrOverlappingTimeWindows,
+		},
+		{
+			name: "overnight window overlapping early window",
+			opts: []ScheduleOption{SetTimeWindows(
+				TimeWindow{Start: clock(22, 0), End: clock(4, 0)},
+				TimeWindow{Start: clock(3, 0), End: clock(6, 0)},
+			)},
+			expectError: ErrOverlappingTimeWindows,
+		},
+		{
+			name: "open ended window overlapping later window",
+			opts: []ScheduleOption{SetTimeWindows(
+				TimeWindow{Start: clock(8, 0)},
+				TimeWindow{Start: clock(20, 0), End: clock(21, 0)},
+			)},
+			expectError: ErrOverlappingTimeWindows,
+		},
+		{
+			name: "empty window",
+			opts: []ScheduleOption{SetTimeWindows(
+				TimeWindow{Start: clock(8, 0), End: clock(8, 0)},
+			)},
+			expectError: ErrInvalidTimeWindow,
+		},
+		{
+			name: "combined with start time",
+			opts: []ScheduleOption{
+				SetStartTime(&startTime),
+				SetTimeWindows(TimeWindow{Start: clock(8, 0), End: clock(12, 0)}),
+			},
+			expectError: ErrConflictingTimeWindows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(30, Minute, tt.opts...)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, schedule)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, schedule)
+			}
+		})
+	}
+}
+
+func TestSchedule_TimeWindowsWithStartDate(t *testing.T) {
+	startDate := parseTime(t, "2024-03-15 00:00:00")
+	schedule, err := New(30, Minute,
+		SetStartDate(&startDate),
+		SetTimeWindows(
+			TimeWindow{Start: clock(13, 0), End: clock(17, 0)},
+			TimeWindow{Start: clock(8, 0), End: clock(12, 0)},
+		),
+	)
+	require.NoError(t, err)
+
+	next := schedule.Next(parseTime(t, "2024-03-11 10:00:00"))
+	assert.Equal(t, parseTime(t, "2024-03-15 08:00:00"), next)
+}
+
+func TestSchedule_WeeklyTimeWindows(t *testing.T) {
+	office := TimeWindow{Start: clock(9, 0), End: clock(18, 0)}
+	table := map[time.Weekday][]TimeWindow{
+		time.Monday:    {office},
+		time.Tuesday:   {office},
+		time.Wednesday: {office},
+		time.Thursday:  {office},
+		time.Friday:    {office},
+		time.Saturday:  {{Start: clock(10, 0), End: clock(14, 0)}},
+	}
+
+	tests := []struct {
+		name     string
+		current  string
+		expected string
+	}{
+		{
+			name:     "weekday before office hours",
+			current:  "2024-03-11 08:00:00", // Monday
+			expected: "2024-03-11 09:00:00",
+		},
+		{
+			name:     "friday evening moves to saturday window",
+			current:  "2024-03-15 17:45:00", // Friday
+			expected: "2024-03-16 10:00:00", // Saturday
+		},
+		{
+			name:     "inside saturday window",
+			current:  "2024-03-16 11:00:00",
+			expected: "2024-03-16 11:30:00",
+		},
+		{
+			name:     "saturday after window skips sunday",
+			current:  "2024-03-16 13:45:00",
+			expected: "2024-03-18 09:00:00", // Monday
+		},
+		{
+			name:     "sunday has no window",
+			current:  "2024-03-17 12:00:00",
+			expected: "2024-03-18 09:00:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(30, Minute, SetWeeklyTimeWindows(table))
+			require.NoError(t, err)
+
+			current := parseTime(t, tt.current)
+			expected := parseTime(t, tt.expected)
+
+			next := schedule.Next(current)
+			assert.Equal(t, expected, next)
+		})
+	}
+}
+
+func TestSchedule_WeeklyTimeWindowsFindNextAllowedDay(t *testing.T) {
+	schedule, err := New(1, Hour, SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+		time.Wednesday: {{Start: clock(7, 0), End: clock(9, 0)}},
+	}))
+	require.NoError(t, err)
+
+	next := schedule.findNextAllowedDay(parseTime(t, "2024-03-11 12:00:00")) // Monday
+	assert.Equal(t, parseTime(t, "2024-03-13 07:00:00"), next)
+}
+
+func TestSchedule_WeeklyTimeWindowsValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []ScheduleOption
+		expectError error
+	}{
+		{
+			name: "overnight window running into next day's window",
+			opts: []ScheduleOption{SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+				time.Friday:   {{Start: clock(22, 0), End: clock(4, 0)}},
+				time.Saturday: {{Start: clock(3, 0), End: clock(6, 0)}},
+			})},
+			expectError: ErrOverlappingTimeWindows,
+		},
+		{
+			name: "saturday overnight window wraps into sunday",
+			opts: []ScheduleOption{SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+				time.Saturday: {{Start: clock(22, 0), End: clock(4, 0)}},
+				time.Sunday:   {{Start: clock(3, 0), End: clock(6, 0)}},
+			})},
+			expectError: ErrOverlappingTimeWindows,
+		},
+		{
+			name: "same window on different days",
+			opts: []ScheduleOption{SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+				time.Friday:   {{Start: clock(22, 0), End: clock(4, 0)}},
+				time.Saturday: {{Start: clock(22, 0), End: clock(4, 0)}},
+			})},
+		},
+		{
+			name: "invalid weekday",
+			opts: []ScheduleOption{SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+				time.Weekday(7): {{Start: clock(9, 0), End: clock(10, 0)}},
+			})},
+			expectError: ErrInvalidWeekday,
+		},
+		{
+			name: "combined with time windows",
+			opts: []ScheduleOption{
+				SetTimeWindows(TimeWindow{Start: clock(8, 0), End: clock(12, 0)}),
+				SetWeeklyTimeWindows(map[time.Weekday][]TimeWindow{
+					time.Monday: {{Start: clock(9, 0), End: clock(10, 0)}},
+				}),
+			},
+			expectError: ErrConflictingTimeWindows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := New(30, Minute, tt.opts...)
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, schedule)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, schedule)
+			}
+		})
+	}
+}
+
+func TestSchedule_RoundUpMatchesStepping(t *testing.T) {
+	newYork, _ := loadDSTZones(t)
+
+	opens := []time.Time{
+		parseTime(t, "2025-01-06 09:00:00"),
+		// Clocks fall back during the day
+		parseZoned(t, newYork, "2025-11-02 00:00:00 -0400"),
+	}
+	offsets := []time.Duration{
+		0, time.Nanosecond, 59 * time.Second, 7 * time.Minute,
+		90*time.Minute + 1, 14*time.Hour + 59*time.Minute + 59*time.Second,
+	}
+
+	for unit := Second; unit <= BusinessDay; unit++ {
+		for _, interval := range []int{1, 7, 45} {
+			schedule, err := New(interval, unit, DisablePrecision())
+			require.NoError(t, err)
+
+			for _, open := range opens {
+				for _, offset := range offsets {
+					at := open.Add(offset)
+					assert.Equal(t, schedule.roundUpByStepping(open, at), schedule.roundUp(open, at),
+						"every %d %s from %s", interval, unit, at)
+				}
+			}
+		}
+	}
+}